@@ -4,29 +4,27 @@ import (
 	"context"
 	"fmt"
 	"os"
-	"os/exec"
 	"strings"
 
-	openai "github.com/sashabaranov/go-openai"
 	"github.com/spf13/cobra"
 )
 
 var (
-	inputFile string
-	resName   string
-	namespace string
+	inputFile       string
+	resName         string
+	namespace       string
+	analyzeEvidence EvidenceOptions
 )
 
 var AnalyzeCmd = &cobra.Command{
-	Use:   "analyze [resource-type] [resource-name] --ns <namespace> [question]",
+	Use:   "analyze [resource-type] [resource-name] [--ns <namespace>] [question]",
 	Short: "Analyze Kubernetes resources or errors using AI",
 	Long:  "Analyze raw kubectl outputs (describe, logs, events, etc.) or YAML manifests and diagnose Kubernetes issues with the help of AI.",
 	Args:  cobra.MinimumNArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		apiKey := os.Getenv("OPENAI_API_KEY")
-		if apiKey == "" {
-			fmt.Println("❌ OPENAI_API_KEY environment variable not set.")
-			return
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := NewProvider()
+		if err != nil {
+			return err
 		}
 
 		// Eğer --name boşsa ve en az 2 argüman varsa (resource-type ve resource-name), otomatik doldur
@@ -39,91 +37,86 @@ var AnalyzeCmd = &cobra.Command{
 
 		// Eğer hem file hem name yoksa hata ver
 		if resName == "" && inputFile == "" {
-			fmt.Println("❌ Please provide either a file (-f) or a resource name (--name) with namespace (--ns).")
-			return
+			return usageErrorf("please provide either a file (-f) or a resource name (--name)")
 		}
 
-		// Eğer kaynak adı kullanılıyorsa namespace zorunlu olsun
-		if resName != "" && namespace == "" {
-			fmt.Println("❌ Namespace (--ns) is required when using resource name (--name) or positional args.")
-			return
+		// Kaynak adı kullanılıyorsa cluster'a bağlan; namespace verilmediyse -n veya kubeconfig'deki namespace kullanılır
+		var cluster *Cluster
+		if resName != "" {
+			if cluster, err = NewCluster(); err != nil {
+				return err
+			}
+			namespace = cluster.ResolveNamespace(namespace)
 		}
 
 		question := strings.Join(args, " ")
 		if question == "" {
-			fmt.Println("❌ Please provide a question for AI analysis.")
-			return
+			return usageErrorf("please provide a question for AI analysis")
 		}
 
 		SaveToHistory("analyze", fmt.Sprintf("resName=%s ns=%s file=%s question=%s", resName, namespace, inputFile, question))
 
-		kubeData, err := getKubernetesData()
+		kubeData, err := getKubernetesData(cmd.Context(), cluster)
 		if err != nil {
-			fmt.Printf("❌ Error collecting Kubernetes data: %v\n", err)
-			return
+			return err
 		}
 
-		fullPrompt := fmt.Sprintf(`Analyze the following Kubernetes output and answer the user's question.
-
---- Start of Kubernetes Output ---
-%s
---- End of Kubernetes Output ---
-
-User question: %s`, kubeData, question)
-
-		client := openai.NewClient(apiKey)
-		resp, err := client.CreateChatCompletion(
-			context.Background(),
-			openai.ChatCompletionRequest{
-				Model: Model,
-				Messages: []openai.ChatCompletionMessage{
-					{
-						Role: openai.ChatMessageRoleSystem,
-						Content: `You are a certified Kubernetes expert.
-Your task is to analyze raw kubectl command outputs (describe, logs, events, etc.) or YAML manifests and help diagnose issues.
-If a pod is crashing, stuck, or unhealthy, identify root causes such as image pull errors, readiness probe failures, or insufficient resources.
-Provide detailed reasoning, potential root causes, and suggested fixes.`,
-					},
-					{
-						Role:    openai.ChatMessageRoleUser,
-						Content: fullPrompt,
-					},
-				},
-				MaxTokens: MaxTokens,
-			},
-		)
-
+		promptData := PromptData{Resource: resName, Namespace: namespace, Question: question}
+		systemPrompt, err := renderPrompt("analyze-system", promptData)
+		if err != nil {
+			return err
+		}
+		// Modelin context penceresine sığmayan girdi parça parça özetlenir
+		kubeData, condenseUsage, err := condense(cmd.Context(), client, systemPrompt, kubeData, question)
 		if err != nil {
-			fmt.Printf("❌ OpenAI error: %v\n", err)
-			return
+			return err
+		}
+		promptData.Data = kubeData
+		fullPrompt, err := renderPrompt("analyze", promptData)
+		if err != nil {
+			return err
 		}
 
+		// Cluster'daki bir kaynak inceleniyorsa model ek kanıt toplamak için araç çağırabilir
 		fmt.Println("\n🤖 AI Analysis:")
-		fmt.Println(strings.TrimSpace(resp.Choices[0].Message.Content))
+		resp, evidence, err := investigate(cmd.Context(), client, cluster, systemPrompt, fullPrompt, namespace, os.Stdout)
+		if err != nil {
+			return err
+		}
+		printEvidence(evidence)
+		printRedactions()
+		printUsage(resp.Model, addUsage(resp.Usage, condenseUsage))
+		return nil
 	},
 }
 
 // Kubernetes çıktısını dosyadan ya da cluster'dan al
-func getKubernetesData() (string, error) {
+func getKubernetesData(ctx context.Context, cluster *Cluster) (string, error) {
 	if inputFile != "" {
-		content, err := os.ReadFile(inputFile)
+		content, err := readInput(inputFile)
+		if err != nil {
+			return "", err
+		}
+		return prepareInput(content, inputName(inputFile)), nil
+	} else if resName != "" && cluster != nil {
+		resourceType, name, err := splitResourceName(resName)
 		if err != nil {
-			return "", fmt.Errorf("failed to read file %s: %w", inputFile, err)
+			return "", err
 		}
-		return string(content), nil
-	} else if resName != "" && namespace != "" {
-		cmd := exec.Command("kubectl", "get", resName, "-n", namespace, "-o", "yaml")
-		output, err := cmd.CombinedOutput()
+		evidence, err := cluster.CollectEvidence(ctx, resourceType, name, namespace, analyzeEvidence)
 		if err != nil {
-			return "", fmt.Errorf("failed to get resource: %w\nOutput: %s", err, output)
+			return "", err
 		}
-		return string(output), nil
+		return evidence.String(), nil
 	}
-	return "", fmt.Errorf("please provide either --file or both --name and --ns parameters")
+	return "", usageErrorf("please provide either --file or --name")
 }
 
 func init() {
-	AnalyzeCmd.Flags().StringVarP(&inputFile, "file", "f", "", "Path to a file containing kubectl output")
+	AnalyzeCmd.Flags().StringVarP(&inputFile, "file", "f", "", "Path to a file containing kubectl output (- reads stdin)")
 	AnalyzeCmd.Flags().StringVar(&resName, "name", "", "Name and type of Kubernetes resource (e.g. deployment/nginx)")
-	AnalyzeCmd.Flags().StringVar(&namespace, "ns", "", "Namespace of the resource (required)")
+	AnalyzeCmd.Flags().StringVar(&namespace, "ns", "", "Namespace of the resource (default: -n or the kubeconfig context namespace)")
+	AnalyzeCmd.Flags().DurationVar(&analyzeEvidence.Since, "since", 0, "Only collect logs newer than this duration (e.g. 30m, 2h)")
+	AnalyzeCmd.Flags().Int64Var(&analyzeEvidence.Tail, "tail", 100, "Number of log lines to collect per container (0 for all)")
+	AnalyzeCmd.Flags().StringVar(&analyzeEvidence.Container, "container", "", "Only collect logs of this container")
 }
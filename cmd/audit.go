@@ -1,58 +1,126 @@
 package cmd
 
 import (
-	"context"
+	"errors"
 	"fmt"
 	"os"
-	"os/exec"
+	"slices"
 	"strings"
 
 	openai "github.com/sashabaranov/go-openai"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 var (
 	auditInputFile string
 	auditResName   string
 	auditNamespace string
+	auditOutput    string
+	auditFailOn    string
+	auditNoAI      bool
+	auditPSS       string
+	auditPolicyDir string
+
+	auditFix        bool
+	auditFixOutput  string
+	auditPinDigests bool
+
+	auditBaseline      string
+	auditWriteBaseline bool
 )
 
 var AuditCmd = &cobra.Command{
 	Use:   "audit [resource-type] [resource-name] [flags] [optional: question]",
 	Short: "Audit Kubernetes resources for security risks using AI",
-	Long:  "Analyze Kubernetes resources (from file or live cluster) to detect security risks, misconfigurations, and policy violations using AI.",
-	Run: func(cmd *cobra.Command, args []string) {
-		apiKey := os.Getenv("OPENAI_API_KEY")
-		if apiKey == "" {
-			fmt.Println("❌ OPENAI_API_KEY environment variable not set.")
-			return
+	Long: `Analyze Kubernetes resources (from file or live cluster) to detect security risks, misconfigurations, and policy violations using AI.
+
+Findings are reported with a rule ID, severity, resource, field path, description and remediation,
+as a table by default, or as JSON, SARIF 2.1.0 or JUnit XML with -o json|sarif|junit (--format is an alias).
+For -f input, findings carry the file path and the line of the offending field.
+Without a file or resource, the question is answered as prose.
+
+With --fail-on <severity>, audit exits with code 5 when any finding is at or above that severity,
+so it can gate a CI pipeline.
+
+Built-in rules always run first: privileged containers, running as root, host network, hostPath volumes,
+missing resource limits, :latest images, missing probes and automounted service account tokens.
+Their findings are deterministic and are always reported; the AI explains them and adds findings the rules
+do not cover. --no-ai runs the rules alone, without contacting any AI provider.
+
+Organization policies written in CEL are read from --policy-dir (default: $KUBE_AI_POLICIES or
+~/.config/kube-ai/policies) and run next to the built-in rules; their findings have source "policy".
+
+--fix writes a copy of the -f manifest with safe remediations: securityContext defaults
+(runAsNonRoot, RuntimeDefault seccomp, no privilege escalation, dropped capabilities,
+readOnlyRootFilesystem), resource request and limit placeholders, and, with --pin-digests, the
+image digests of running pods. Only missing fields are added. A unified diff is printed.
+
+--pss baseline|restricted adds the controls of that Pod Security Standard to the built-in rules.
+Every Pod and pod template is evaluated, and the violated controls are listed with the field change
+that makes the workload comply. A built-in finding on the same field as a PSS control is reported once,
+as the PSS finding.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := validateFindingsFlags(); err != nil {
+			return err
+		}
+		if auditPSS != "" && auditPSS != PSSBaseline && auditPSS != PSSRestricted {
+			return usageErrorf("invalid --pss level %q (valid: %s, %s)", auditPSS, PSSBaseline, PSSRestricted)
+		}
+		if auditFix && auditInputFile == "" {
+			return usageErrorf("--fix needs a manifest file (-f)")
+		}
+		if auditFix && auditInputFile == "-" && auditFixOutput == "" {
+			return usageErrorf("--fix with stdin input needs --fix-output")
+		}
+		if (auditFixOutput != "" || auditPinDigests) && !auditFix {
+			return usageErrorf("--fix-output and --pin-digests need --fix")
+		}
+		var err error
+		if auditPolicyDir != "" {
+			orgPolicies, err = loadPolicies(auditPolicyDir, true)
+		} else {
+			orgPolicies, err = loadPolicies(policyDir(), false)
+		}
+		if err != nil {
+			return err
 		}
 
-		var auditData string
+		var auditData, auditSource string
 		var userQuestion string
 
 		// inputFile varsa dosyadan oku
 		if auditInputFile != "" {
-			content, err := os.ReadFile(auditInputFile)
+			content, err := readInput(auditInputFile)
+			if err != nil {
+				return err
+			}
+			auditSource = content
+			if auditFix && strings.HasPrefix(strings.TrimSpace(content), "{") {
+				return usageErrorf("--fix supports YAML manifests only")
+			}
+			auditData = prepareInput(content, inputName(auditInputFile))
+		} else if auditResName != "" {
+			// resource varsa cluster'dan çek; namespace verilmediyse -n veya kubeconfig'deki kullanılır
+			resourceType, name, err := splitResourceName(auditResName)
 			if err != nil {
-				fmt.Printf("❌ Failed to read file %s: %v\n", auditInputFile, err)
-				return
+				return err
 			}
-			auditData = string(content)
-		} else if auditResName != "" && auditNamespace != "" {
-			// resource ve namespace varsa cluster'dan çek
-			cmd := exec.Command("kubectl", "get", auditResName, "-n", auditNamespace, "-o", "yaml")
-			output, err := cmd.CombinedOutput()
+			cluster, err := NewCluster()
 			if err != nil {
-				fmt.Printf("❌ Failed to fetch resource from cluster: %v\nOutput: %s\n", err, output)
-				return
+				return err
+			}
+			auditNamespace = cluster.ResolveNamespace(auditNamespace)
+			if auditData, err = cluster.GetYAML(cmd.Context(), resourceType, name, auditNamespace); err != nil {
+				return err
 			}
-			auditData = string(output)
 		} else if len(args) > 0 {
 			userQuestion = strings.Join(args, " ")
 		} else {
-			fmt.Println("❌ Please provide a file (-f), a resource name (--name and --ns), or a question as an argument.")
-			return
+			return usageErrorf("please provide a file (-f), a resource name (--name), or a question as an argument")
+		}
+		if (auditNoAI || auditPSS != "") && auditData == "" {
+			return usageErrorf("--no-ai and --pss need a file (-f) or a resource name (--name)")
 		}
 
 		if userQuestion == "" {
@@ -61,46 +129,211 @@ var AuditCmd = &cobra.Command{
 
 		SaveToHistory("audit", fmt.Sprintf("name=%s ns=%s file=%s question=%s", auditResName, auditNamespace, auditInputFile, userQuestion))
 
-		fullPrompt := fmt.Sprintf(`Kubernetes Resource to Audit:
----
-%s
----
-Task: %s
-`, auditData, userQuestion)
-
-		client := openai.NewClient(apiKey)
-		resp, err := client.CreateChatCompletion(
-			context.Background(),
-			openai.ChatCompletionRequest{
-				Model: Model,
-				Messages: []openai.ChatCompletionMessage{
-					{
-						Role: openai.ChatMessageRoleSystem,
-						Content: `You are a Kubernetes security auditor.
-Your job is to detect any security vulnerabilities, misconfigurations, and best practice violations in Kubernetes manifests or outputs.
-Focus on issues like missing resource limits, excessive permissions, absent network policies, and insecure container settings.`,
-					},
-					{
-						Role:    openai.ChatMessageRoleUser,
-						Content: fullPrompt,
-					},
-				},
-				MaxTokens: MaxTokens,
-			},
-		)
+		// Dahili kurallar (ve --pss kontrolleri) AI'dan bağımsız ve her zaman çalışır
+		var ruleFindings []Finding
+		var verdicts []pssVerdict
+		inputLabel := auditResName
+		if auditInputFile != "" {
+			inputLabel = inputName(auditInputFile)
+		}
+		objects := manifestObjects(auditData, inputLabel)
+		ruleFindings = runRules(objects)
+		if auditPSS != "" {
+			var pssFindings []Finding
+			pssFindings, verdicts = evaluatePSS(objects, auditPSS)
+			ruleFindings = mergePSSFindings(ruleFindings, pssFindings)
+		}
+		// Kurum politikaları dahili kuralların yanında çalışır
+		if len(orgPolicies) > 0 {
+			ruleFindings = append(ruleFindings, runPolicies(orgPolicies, objects)...)
+			sortFindings(ruleFindings)
+		}
+		reportRules := func() error {
+			if auditOutput == "table" {
+				fmt.Println("\n🔍 Audit Result (built-in rules):")
+			}
+			reported, err := reportAuditFindings(ruleFindings, auditSource, verdicts)
+			if err != nil {
+				return err
+			}
+			if auditFix {
+				if err := runFix(cmd.Context(), auditSource); err != nil {
+					return err
+				}
+			}
+			return failOnFindings(reported)
+		}
+		if auditNoAI {
+			return reportRules()
+		}
+		// AI başarısız olsa da dahili kuralların bulguları bildirilir ve --fail-on uygulanır
+		aiFailed := func(err error) error {
+			if auditData == "" {
+				return err
+			}
+			return reportWithoutAI(err, reportRules)
+		}
 
+		client, err := NewProvider()
 		if err != nil {
-			fmt.Printf("❌ OpenAI error: %v\n", err)
-			return
+			return aiFailed(err)
 		}
 
+		promptData := PromptData{Resource: auditResName, Namespace: auditNamespace, Question: userQuestion}
+		systemPrompt, err := renderPrompt("audit-system", promptData)
+		if err != nil {
+			return err
+		}
+		// Modelin context penceresine sığmayan girdi parça parça özetlenir
+		condensed, condenseUsage, err := condense(cmd.Context(), client, systemPrompt, auditData, userQuestion)
+		if err != nil {
+			return aiFailed(err)
+		}
+		promptData.Data = condensed
+		fullPrompt, err := renderPrompt("audit", promptData)
+		if err != nil {
+			return err
+		}
+
+		if auditData != "" {
+			aiFindings, resp, err := requestFindings(cmd.Context(), client, systemPrompt, fullPrompt+ruleFindingsPrompt(ruleFindings))
+			if err != nil {
+				return aiFailed(err)
+			}
+			if auditOutput == "table" {
+				fmt.Println("\n🔍 AI Audit Result:")
+			}
+			findings := mergeFindings(ruleFindings, aiFindings)
+			fillNamespaces(findings, objects)
+			reported, err := reportAuditFindings(findings, auditSource, verdicts)
+			if err != nil {
+				return err
+			}
+			printUsage(resp.Model, addUsage(resp.Usage, condenseUsage))
+			if auditFix {
+				if err := runFix(cmd.Context(), auditSource); err != nil {
+					return err
+				}
+			}
+			return failOnFindings(reported)
+		}
+
+		// Yalnızca soru verildiyse serbest metin cevap
 		fmt.Println("\n🔍 AI Audit Result:")
-		fmt.Println(strings.TrimSpace(resp.Choices[0].Message.Content))
+		resp, err := complete(cmd.Context(), client, openai.ChatCompletionRequest{
+			Model: Model,
+			Messages: []openai.ChatCompletionMessage{
+				{
+					Role:    openai.ChatMessageRoleSystem,
+					Content: systemPrompt,
+				},
+				{
+					Role:    openai.ChatMessageRoleUser,
+					Content: fullPrompt,
+				},
+			},
+			MaxTokens: MaxTokens,
+		}, os.Stdout)
+		if err != nil {
+			return err
+		}
+		printRedactions()
+		printUsage(resp.Model, addUsage(resp.Usage, condenseUsage))
+		return nil
 	},
 }
 
+// reportAuditFindings, bastırılmamış bulguları seçilen biçimde, --pss sonucu
+// ve önem özetiyle yazar ve --fail-on için bildirilen bulguları döndürür.
+func reportAuditFindings(findings []Finding, source string, verdicts []pssVerdict) ([]Finding, error) {
+	if auditInputFile != "" && auditInputFile != "-" {
+		locateFindings(findings, auditInputFile, source)
+	}
+	findings, suppressed, err := applySuppressions(findings)
+	if err != nil {
+		return nil, err
+	}
+	if err := writeFindings(os.Stdout, auditOutput, findings); err != nil {
+		return nil, err
+	}
+	if auditPSS != "" {
+		writePSSVerdicts(statusOut, auditPSS, verdicts)
+	}
+	writeSeveritySummary(statusOut, findings)
+	writeSuppressionSummary(suppressed)
+	printRedactions()
+	return findings, nil
+}
+
+// validateFindingsFlags, bulgu üreten komutların ortak flag'lerini doğrular.
+func validateFindingsFlags() error {
+	if !slices.Contains(findingFormats, auditOutput) {
+		return usageErrorf("invalid output format %q (valid: %s)", auditOutput, strings.Join(findingFormats, ", "))
+	}
+	if auditOutput != "table" {
+		// stdout yalnızca makinenin okuyacağı çıktıyı içersin
+		statusOut = os.Stderr
+	}
+	if auditFailOn != "" && severityRank(auditFailOn) < 0 {
+		return usageErrorf("invalid --fail-on severity %q (valid: %s)", auditFailOn, strings.Join(severities, ", "))
+	}
+	var err error
+	suppressions, err = loadSuppressions(suppressionPath())
+	return err
+}
+
+// addFindingsFlags, bulgu üreten komutlara -o, --fail-on, --no-ai ve bastırma flag'lerini ekler.
+// Değerler audit ile ortaktır; aynı anda yalnızca bir komut çalışır.
+func addFindingsFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&auditOutput, "output", "o", "table", "Output format: table, json, sarif or junit")
+	cmd.Flags().SetAnnotation("output", formatFlagAnnotation, []string{"true"})
+	cmd.Flags().StringVar(&auditFailOn, "fail-on", "", "Exit with code 5 if any finding is at or above this severity (info, low, medium, high, critical)")
+	cmd.Flags().BoolVar(&auditNoAI, "no-ai", false, "Run only the built-in rules, offline, without contacting an AI provider")
+	cmd.Flags().StringVar(&auditBaseline, "baseline", "", "Suppression file of accepted findings (default: "+defaultIgnoreFile+" if it exists)")
+	cmd.Flags().BoolVar(&auditWriteBaseline, "write-baseline", false, "Add the current findings to the suppression file, so later runs report only new findings")
+	// --format, -o için eş anlamlıdır
+	cmd.Flags().SetNormalizeFunc(func(f *pflag.FlagSet, name string) pflag.NormalizedName {
+		if name == "format" {
+			name = "output"
+		}
+		return pflag.NormalizedName(name)
+	})
+}
+
+// reportWithoutAI, AI isteği başarısız olduğunda uyarı yazar ve dahili
+// bulguları report ile yine de bildirir. --fail-on eşiği aşılırsa FindingsError,
+// aşılmazsa AI hatası döner. Ctrl-C ile iptal ve AI dışı hatalar (örn. kullanım
+// hataları) olduğu gibi döner.
+func reportWithoutAI(aiErr error, report func() error) error {
+	var classified *AIError
+	if !errors.As(aiErr, &classified) || classified.Kind == AIErrorCancelled {
+		return aiErr
+	}
+	fmt.Fprintf(statusOut, "⚠️ AI request failed, reporting built-in findings only: %v\n", aiErr)
+	if err := report(); err != nil {
+		return err
+	}
+	return aiErr
+}
+
+// failOnFindings, --fail-on eşiğini aşan bulgu varsa FindingsError döndürür.
+func failOnFindings(findings []Finding) error {
+	if auditFailOn != "" {
+		if count := countAtOrAbove(findings, auditFailOn); count > 0 {
+			return &FindingsError{Count: count, Threshold: strings.ToLower(auditFailOn)}
+		}
+	}
+	return nil
+}
+
 func init() {
-	AuditCmd.Flags().StringVarP(&auditInputFile, "file", "f", "", "Path to a file containing Kubernetes manifest")
+	AuditCmd.Flags().StringVarP(&auditInputFile, "file", "f", "", "Path to a file containing Kubernetes manifest (- reads stdin)")
 	AuditCmd.Flags().StringVar(&auditResName, "name", "", "Kubernetes resource type/name (e.g., pod/mypod)")
 	AuditCmd.Flags().StringVar(&auditNamespace, "ns", "", "Namespace of the resource")
+	AuditCmd.Flags().StringVar(&auditPSS, "pss", "", "Evaluate pod templates against a Pod Security Standard level: baseline or restricted")
+	AuditCmd.Flags().BoolVar(&auditFix, "fix", false, "Write a copy of the -f manifest with safe remediations applied and print a unified diff")
+	AuditCmd.Flags().StringVar(&auditFixOutput, "fix-output", "", "Path of the fixed manifest (default: <file>.fixed.yaml next to the input)")
+	AuditCmd.Flags().BoolVar(&auditPinDigests, "pin-digests", false, "With --fix, pin images to the digests that running pods in the cluster use")
+	AuditCmd.Flags().StringVar(&auditPolicyDir, "policy-dir", "", "Directory of CEL organization policies (default: $KUBE_AI_POLICIES or ~/.config/kube-ai/policies)")
+	addFindingsFlags(AuditCmd)
 }
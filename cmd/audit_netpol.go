@@ -0,0 +1,162 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	netpolFiles     []string
+	netpolNamespace string
+	netpolFrom      string
+	netpolTo        string
+	netpolPort      string
+)
+
+var AuditNetpolCmd = &cobra.Command{
+	Use:   "netpol [optional: question]",
+	Short: "Analyze NetworkPolicy isolation and pod-to-pod reachability",
+	Long: `Load the Pods and NetworkPolicies of a namespace from the cluster, or pod templates, NetworkPolicies
+and Namespaces from files (-f, repeatable), and work out which workloads have no ingress or egress
+isolation. A coverage matrix shows the ports each workload can reach on every other one. Pods that
+belong to the same controller are shown as one workload, e.g. deployment/web.
+
+Use --from and --to (with an optional --port such as 8080, 53/UDP or a named port like http) to ask
+whether one workload can reach another, and which policies block it. The AI explains the isolation
+gaps and how to close them unless --no-ai is given.
+
+Without -f, the namespace is --ns or the namespace of the current context. In files, objects without
+a namespace belong to --ns, or to "default".`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := validateFindingsFlags(); err != nil {
+			return err
+		}
+		if (netpolFrom == "") != (netpolTo == "") {
+			return usageErrorf("--from and --to must be given together")
+		}
+		if netpolPort != "" && netpolFrom == "" {
+			return usageErrorf("--port requires --from and --to")
+		}
+
+		var in *netpolObjects
+		var sources []string
+		namespace := netpolNamespace
+		if len(netpolFiles) > 0 {
+			if namespace == "" {
+				namespace = "default"
+			}
+			var objects []map[string]interface{}
+			for _, file := range netpolFiles {
+				content, err := readInput(file)
+				if err != nil {
+					return err
+				}
+				sources = append(sources, content)
+				objects = append(objects, manifestObjects(content, inputName(file))...)
+			}
+			in = netpolFromManifests(objects, namespace)
+		} else {
+			cluster, err := NewCluster()
+			if err != nil {
+				return err
+			}
+			namespace = cluster.ResolveNamespace(namespace)
+			if in, err = cluster.LoadNetpol(cmd.Context(), namespace); err != nil {
+				return err
+			}
+		}
+
+		analysis := analyzeNetpol(in)
+		var answer bytes.Buffer
+		if netpolFrom != "" {
+			src, err := analysis.findEndpoint(netpolFrom)
+			if err != nil {
+				return err
+			}
+			dst, err := analysis.findEndpoint(netpolTo)
+			if err != nil {
+				return err
+			}
+			if err := analysis.answerReach(&answer, src, dst, netpolPort); err != nil {
+				return err
+			}
+		}
+		ruleFindings := analysis.netpolFindings()
+
+		question := strings.Join(args, " ")
+		if question == "" {
+			question = "Explain the network isolation gaps and which NetworkPolicies would close them."
+			if netpolFrom != "" {
+				question = fmt.Sprintf("Explain why %s can or cannot reach %s and what to change.", netpolFrom, netpolTo)
+			}
+		}
+		SaveToHistory("audit netpol", fmt.Sprintf("ns=%s files=%s from=%s to=%s port=%s question=%s",
+			namespace, strings.Join(netpolFiles, ","), netpolFrom, netpolTo, netpolPort, question))
+
+		analysis.writeIsolation(statusOut)
+		analysis.writeMatrix(statusOut)
+		fmt.Fprint(statusOut, answer.String())
+		reportChecks := func() error {
+			if auditOutput == "table" {
+				fmt.Println("\n🔍 NetworkPolicy Audit Result (built-in checks):")
+			}
+			locateFindingsInFiles(ruleFindings, netpolFiles, sources)
+			reported, err := reportAuditFindings(ruleFindings, "", nil)
+			if err != nil {
+				return err
+			}
+			return failOnFindings(reported)
+		}
+		if auditNoAI {
+			return reportChecks()
+		}
+
+		client, err := NewProvider()
+		if err != nil {
+			return reportWithoutAI(err, reportChecks)
+		}
+		promptData := PromptData{Namespace: namespace, Question: question}
+		systemPrompt, err := renderPrompt("audit-netpol-system", promptData)
+		if err != nil {
+			return err
+		}
+		data, condenseUsage, err := condense(cmd.Context(), client, systemPrompt, analysis.netpolText(answer.String()), question)
+		if err != nil {
+			return reportWithoutAI(err, reportChecks)
+		}
+		promptData.Data = data
+		userPrompt, err := renderPrompt("audit-netpol", promptData)
+		if err != nil {
+			return err
+		}
+
+		aiFindings, resp, err := requestFindings(cmd.Context(), client, systemPrompt, userPrompt+ruleFindingsPrompt(ruleFindings))
+		if err != nil {
+			return reportWithoutAI(err, reportChecks)
+		}
+		if auditOutput == "table" {
+			fmt.Println("\n🔍 AI NetworkPolicy Audit Result:")
+		}
+		findings := mergeFindings(ruleFindings, aiFindings)
+		locateFindingsInFiles(findings, netpolFiles, sources)
+		reported, err := reportAuditFindings(findings, "", nil)
+		if err != nil {
+			return err
+		}
+		printUsage(resp.Model, addUsage(resp.Usage, condenseUsage))
+		return failOnFindings(reported)
+	},
+}
+
+func init() {
+	AuditNetpolCmd.Flags().StringArrayVarP(&netpolFiles, "file", "f", nil, "File with pods or workloads, NetworkPolicies and Namespaces; repeat for several files (- reads stdin)")
+	AuditNetpolCmd.Flags().StringVar(&netpolNamespace, "ns", "", "Namespace to analyze (default: namespace of the current context)")
+	AuditNetpolCmd.Flags().StringVar(&netpolFrom, "from", "", "Source workload or pod of a reachability question, e.g. deployment/web")
+	AuditNetpolCmd.Flags().StringVar(&netpolTo, "to", "", "Destination workload or pod of a reachability question")
+	AuditNetpolCmd.Flags().StringVar(&netpolPort, "port", "", "Port of the reachability question: a number, number/protocol (e.g. 53/UDP) or a named container port")
+	addFindingsFlags(AuditNetpolCmd)
+	AuditCmd.AddCommand(AuditNetpolCmd)
+}
@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	rbacFiles         []string
+	rbacNamespace     string
+	rbacIncludeSystem bool
+)
+
+var AuditRBACCmd = &cobra.Command{
+	Use:   "rbac [optional: question]",
+	Short: "Analyze RBAC permissions and privilege escalation paths",
+	Long: `Load Roles, ClusterRoles and their bindings from the cluster or from files (-f, repeatable),
+compute the effective permissions of every user, group and service account, and flag privilege
+escalation paths: wildcard verbs or resources, escalate/bind, impersonate, reading secrets,
+pods/exec and nodes/proxy. The AI explains the risk of each finding unless --no-ai is given.
+
+Without -f, ClusterRoles and ClusterRoleBindings are read together with the Roles and RoleBindings
+of --ns, or of all namespaces when --ns is not given. The default bindings of Kubernetes itself
+(named system:* or labeled kubernetes.io/bootstrapping=rbac-defaults) are skipped unless
+--include-system is given. Bindings to built-in groups such as system:authenticated are always checked.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := validateFindingsFlags(); err != nil {
+			return err
+		}
+
+		var in *rbacObjects
+		var sources []string
+		if len(rbacFiles) > 0 {
+			var objects []map[string]interface{}
+			for _, file := range rbacFiles {
+				content, err := readInput(file)
+				if err != nil {
+					return err
+				}
+				sources = append(sources, content)
+				objects = append(objects, manifestObjects(content, inputName(file))...)
+			}
+			in = rbacFromManifests(objects)
+		} else {
+			cluster, err := NewCluster()
+			if err != nil {
+				return err
+			}
+			if in, err = cluster.LoadRBAC(cmd.Context(), rbacNamespace); err != nil {
+				return err
+			}
+		}
+
+		grants := effectiveGrants(in, rbacIncludeSystem)
+		ruleFindings := analyzeRBAC(grants)
+
+		question := strings.Join(args, " ")
+		if question == "" {
+			question = "Explain the privilege escalation risks in these permissions and how to reduce them."
+		}
+		SaveToHistory("audit rbac", fmt.Sprintf("ns=%s files=%s question=%s", rbacNamespace, strings.Join(rbacFiles, ","), question))
+
+		writePermissions(statusOut, grants)
+		reportChecks := func() error {
+			if auditOutput == "table" {
+				fmt.Println("\n🔍 RBAC Audit Result (built-in checks):")
+			}
+			locateFindingsInFiles(ruleFindings, rbacFiles, sources)
+			reported, err := reportAuditFindings(ruleFindings, "", nil)
+			if err != nil {
+				return err
+			}
+			return failOnFindings(reported)
+		}
+		if auditNoAI {
+			return reportChecks()
+		}
+
+		client, err := NewProvider()
+		if err != nil {
+			return reportWithoutAI(err, reportChecks)
+		}
+		promptData := PromptData{Namespace: rbacNamespace, Question: question}
+		systemPrompt, err := renderPrompt("audit-rbac-system", promptData)
+		if err != nil {
+			return err
+		}
+		data, condenseUsage, err := condense(cmd.Context(), client, systemPrompt, permissionsText(grants), question)
+		if err != nil {
+			return reportWithoutAI(err, reportChecks)
+		}
+		promptData.Data = data
+		userPrompt, err := renderPrompt("audit-rbac", promptData)
+		if err != nil {
+			return err
+		}
+
+		aiFindings, resp, err := requestFindings(cmd.Context(), client, systemPrompt, userPrompt+ruleFindingsPrompt(ruleFindings))
+		if err != nil {
+			return reportWithoutAI(err, reportChecks)
+		}
+		if auditOutput == "table" {
+			fmt.Println("\n🔍 AI RBAC Audit Result:")
+		}
+		findings := mergeFindings(ruleFindings, aiFindings)
+		locateFindingsInFiles(findings, rbacFiles, sources)
+		reported, err := reportAuditFindings(findings, "", nil)
+		if err != nil {
+			return err
+		}
+		printUsage(resp.Model, addUsage(resp.Usage, condenseUsage))
+		return failOnFindings(reported)
+	},
+}
+
+func init() {
+	AuditRBACCmd.Flags().StringArrayVarP(&rbacFiles, "file", "f", nil, "File with Roles, ClusterRoles and bindings; repeat for several files (- reads stdin)")
+	AuditRBACCmd.Flags().StringVar(&rbacNamespace, "ns", "", "Namespace of the Roles and RoleBindings to load (default: all namespaces)")
+	AuditRBACCmd.Flags().BoolVar(&rbacIncludeSystem, "include-system", false, "Include the default system:* bindings of Kubernetes")
+	addFindingsFlags(AuditRBACCmd)
+	AuditCmd.AddCommand(AuditRBACCmd)
+}
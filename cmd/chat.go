@@ -1,7 +1,6 @@
 package cmd
 
 import (
-	"context"
 	"fmt"
 	"os"
 	"regexp"
@@ -19,11 +18,10 @@ var ChatCmd = &cobra.Command{
 	Long: `Ask AI how to perform Kubernetes tasks.
 If you mention or provide a YAML file, it will read that file and give more context-aware command suggestions.`,
 	Args: cobra.MinimumNArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		apiKey := os.Getenv("OPENAI_API_KEY")
-		if apiKey == "" {
-			fmt.Println("❌ OPENAI_API_KEY environment variable not set.")
-			return
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := NewProvider()
+		if err != nil {
+			return err
 		}
 
 		question := strings.Join(args, " ")
@@ -32,70 +30,54 @@ If you mention or provide a YAML file, it will read that file and give more cont
 		yamlContent := ""
 
 		if chatInputFile != "" {
-			content, err := os.ReadFile(chatInputFile)
+			content, err := readInput(chatInputFile)
 			if err != nil {
-				fmt.Printf("❌ Failed to read file %s: %v\n", chatInputFile, err)
-				return
+				return err
 			}
-			yamlContent = string(content)
+			yamlContent = redactor().Text(content, inputName(chatInputFile))
 		} else {
 			matches := regexp.MustCompile(`[\w\-_]+\.ya?ml`).FindStringSubmatch(question)
 			if len(matches) > 0 {
 				detectedFile := matches[0]
 				if _, err := os.Stat(detectedFile); err == nil {
-					content, err := os.ReadFile(detectedFile)
+					content, err := readInput(detectedFile)
 					if err == nil {
-						yamlContent = string(content)
+						yamlContent = redactor().Text(content, detectedFile)
 					}
 				}
 			}
 		}
 
-		systemPrompt := `You are a Kubernetes expert and CLI assistant.
-Always answer user questions with short and clear Kubernetes CLI examples, YAML snippets, or precise step-by-step instructions.
-Focus on practical guidance only. Example:
-- "kubectl apply -f filename.yaml"
-- "kubectl get pods --namespace=my-namespace"`
-
-		if yamlContent != "" {
-			systemPrompt += fmt.Sprintf(`
-
-The user also has the following YAML file open:
-
---- YAML START ---
-%s
---- YAML END ---`, yamlContent)
+		// YAML dosyası varsa sistem şablonunda {{.Data}} olarak eklenir
+		systemPrompt, userPrompt, err := renderPrompts("chat", PromptData{Namespace: KubeNamespace, Question: question, Data: yamlContent})
+		if err != nil {
+			return err
 		}
 
-		client := openai.NewClient(apiKey)
-		resp, err := client.CreateChatCompletion(
-			context.Background(),
-			openai.ChatCompletionRequest{
-				Model: Model,
-				Messages: []openai.ChatCompletionMessage{
-					{
-						Role:    openai.ChatMessageRoleSystem,
-						Content: systemPrompt,
-					},
-					{
-						Role:    openai.ChatMessageRoleUser,
-						Content: question,
-					},
+		fmt.Println("\n🤖 AI Kubernetes Assistant:")
+		resp, err := complete(cmd.Context(), client, openai.ChatCompletionRequest{
+			Model: Model,
+			Messages: []openai.ChatCompletionMessage{
+				{
+					Role:    openai.ChatMessageRoleSystem,
+					Content: systemPrompt,
+				},
+				{
+					Role:    openai.ChatMessageRoleUser,
+					Content: userPrompt,
 				},
-				MaxTokens: MaxTokens,
 			},
-		)
-
+			MaxTokens: MaxTokens,
+		}, os.Stdout)
 		if err != nil {
-			fmt.Printf("❌ OpenAI error: %v\n", err)
-			return
+			return err
 		}
-
-		fmt.Println("\n🤖 AI Kubernetes Assistant:")
-		fmt.Println(strings.TrimSpace(resp.Choices[0].Message.Content))
+		printRedactions()
+		printUsage(resp.Model, resp.Usage)
+		return nil
 	},
 }
 
 func init() {
-	ChatCmd.Flags().StringVarP(&chatInputFile, "file", "f", "", "Optional path to a YAML file for context-aware answers")
+	ChatCmd.Flags().StringVarP(&chatInputFile, "file", "f", "", "Optional path to a YAML file for context-aware answers (- reads stdin)")
 }
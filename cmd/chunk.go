@@ -0,0 +1,221 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	openai "github.com/sashabaranov/go-openai"
+)
+
+// Modellerin context penceresi (token). Tam eşleşme yoksa en uzun önek
+// kullanılır; bilinmeyen modeller için defaultContextSize geçerlidir ve
+// --context-size ile ezilebilir.
+var defaultContextSizes = map[string]int{
+	"gpt-4o":        128000,
+	"gpt-4o-mini":   128000,
+	"gpt-4.1":       1047576,
+	"gpt-4.1-mini":  1047576,
+	"gpt-4.1-nano":  1047576,
+	"gpt-4-turbo":   128000,
+	"gpt-4":         8192,
+	"gpt-3.5-turbo": 16385,
+	"o1":            200000,
+	"o1-mini":       128000,
+	"o3":            200000,
+	"o3-mini":       200000,
+	"o4-mini":       200000,
+	"llama3":        8192,
+	"llama3.1":      131072,
+	"llama3.2":      131072,
+	"mistral":       32768,
+	"qwen2.5":       32768,
+}
+
+const (
+	// Bilinmeyen modeller için varsayılan context penceresi; küçük tutulur ki
+	// yerel modellerde istek sessizce kırpılmasın.
+	defaultContextSize = 8192
+	// Prompt çerçevesi, araç tanımları ve token tahminindeki hata payı için ayrılan pay.
+	contextReserve = 2048
+	// Yaklaşık token hesabı: İngilizce metin ve YAML için ~4 karakter = 1 token.
+	charsPerToken = 4
+	// Parçaların eşzamanlı özetlenme sayısı.
+	chunkWorkers = 4
+	// Özetlerin de sığmadığı durumda en fazla kaç tur daha özetleneceği.
+	maxReduceRounds = 3
+	// Bir parçaya soru düşüldükten sonra kalması gereken en az token sayısı.
+	minChunkBudget = 256
+	// Tüm özetleme turlarında yapılabilecek en fazla parça isteği.
+	maxChunkCalls = 64
+)
+
+// lookupContextSize, model için context penceresini bulur.
+func lookupContextSize(model string) int {
+	if ContextSize > 0 {
+		return ContextSize
+	}
+	model = strings.ToLower(model)
+	if size, ok := defaultContextSizes[model]; ok {
+		return size
+	}
+	best := ""
+	for name := range defaultContextSizes {
+		if strings.HasPrefix(model, name) && len(name) > len(best) {
+			best = name
+		}
+	}
+	if best == "" {
+		return defaultContextSize
+	}
+	return defaultContextSizes[best]
+}
+
+// estimateTokens, metnin yaklaşık token sayısıdır.
+func estimateTokens(text string) int {
+	return len(text)/charsPerToken + 1
+}
+
+// inputBudget, sistem prompt'u ve cevap için ayrılan token'lar düşüldükten
+// sonra girdi verisine kalan token sayısıdır. Pencere bunlara yetmiyorsa 0'dır.
+func inputBudget(systemPrompt string) int {
+	return max(lookupContextSize(Model)-MaxTokens-estimateTokens(systemPrompt)-contextReserve, 0)
+}
+
+// splitChunks, metni satır sınırlarından en fazla maxChars karakterlik parçalara böler.
+// maxChars pozitif değilse metin tek parça döner.
+func splitChunks(text string, maxChars int) []string {
+	if maxChars <= 0 {
+		return []string{text}
+	}
+	var chunks []string
+	var current strings.Builder
+	for _, line := range strings.SplitAfter(text, "\n") {
+		for len(line) > maxChars {
+			if current.Len() > 0 {
+				chunks = append(chunks, current.String())
+				current.Reset()
+			}
+			chunks = append(chunks, line[:maxChars])
+			line = line[maxChars:]
+		}
+		if current.Len()+len(line) > maxChars {
+			chunks = append(chunks, current.String())
+			current.Reset()
+		}
+		current.WriteString(line)
+	}
+	if current.Len() > 0 {
+		chunks = append(chunks, current.String())
+	}
+	return chunks
+}
+
+const chunkSystemPrompt = `You are a Kubernetes expert helping with a task on an input that is too large to read at once.
+You receive one part of the input. Extract everything in this part that is relevant to the task:
+errors, warnings, failing components, suspicious configuration, timestamps of key events and exact error messages.
+Be concise and factual. Do not answer the task itself; your notes are combined with the notes of the other parts.`
+
+// condense, modelin context penceresine sığmayan veriyi parçalara böler, her
+// parçadan görevle ilgili notlar çıkarır (map) ve notları tek bir metinde
+// birleştirir (reduce). Veri zaten sığıyorsa olduğu gibi döner. Son sentez
+// çağrıyı yapan komutun normal isteğidir. Harcanan token'lar döndürülür.
+func condense(ctx context.Context, client Provider, systemPrompt, data, task string) (string, openai.Usage, error) {
+	var usage openai.Usage
+	budget := inputBudget(systemPrompt)
+	if estimateTokens(data) <= budget {
+		return data, usage, nil
+	}
+
+	// Pencere, cevap ve prompt payından sonra en küçük parçaya bile yetmiyorsa bölmek işe yaramaz
+	if inputBudget(chunkSystemPrompt) < minChunkBudget {
+		return "", usage, usageErrorf("the ~%d-token context of model %s leaves no room for input data after --max-tokens %d and the prompt; raise --context-size or lower --max-tokens",
+			lookupContextSize(Model), Model, MaxTokens)
+	}
+	// Soru her parçayla birlikte gönderilir; veriye yer kalmıyorsa bölmek işe yaramaz
+	chunkBudget := inputBudget(chunkSystemPrompt) - estimateTokens(task)
+	if chunkBudget < minChunkBudget {
+		return "", usage, usageErrorf("the question is ~%d tokens and leaves no room for input data in the ~%d-token context of model %s; shorten the question or raise --context-size",
+			estimateTokens(task), lookupContextSize(Model), Model)
+	}
+
+	fmt.Fprintf(statusOut, "🧩 Input is ~%d tokens, more than the ~%d-token budget of model %s; analyzing it in parts...\n",
+		estimateTokens(data), budget, Model)
+
+	calls := 0
+	for round := 1; estimateTokens(data) > budget; round++ {
+		if round > maxReduceRounds {
+			return "", usage, &AIError{Kind: AIErrorContextLength, Err: fmt.Errorf("input is still ~%d tokens after %d summarization rounds", estimateTokens(data), maxReduceRounds)}
+		}
+
+		chunks := splitChunks(data, chunkBudget*charsPerToken)
+		// Her parça bir model isteğidir; çok büyük girdiler sessizce yüzlerce istek yapmasın
+		if calls += len(chunks); calls > maxChunkCalls {
+			return "", usage, usageErrorf("the input is ~%d tokens and would need more than %d model calls to analyze in parts; narrow the input (e.g. --tail, --since, a namespace) or raise --context-size",
+				estimateTokens(data), maxChunkCalls)
+		}
+		notes, chunkUsage, err := summarizeChunks(ctx, client, chunks, task)
+		usage = addUsage(usage, chunkUsage)
+		if err != nil {
+			return "", usage, err
+		}
+
+		var b strings.Builder
+		fmt.Fprintf(&b, "The original input was too large and was split into %d parts. Below are the relevant notes extracted from each part, in order.\n\n", len(chunks))
+		for i, note := range notes {
+			fmt.Fprintf(&b, "### Part %d/%d\n%s\n\n", i+1, len(chunks), strings.TrimSpace(note))
+		}
+		data = b.String()
+	}
+	return data, usage, nil
+}
+
+// summarizeChunks, parçaları en fazla chunkWorkers eşzamanlı istekle özetler.
+func summarizeChunks(ctx context.Context, client Provider, chunks []string, task string) ([]string, openai.Usage, error) {
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		usage    openai.Usage
+		firstErr error
+		notes    = make([]string, len(chunks))
+		sem      = make(chan struct{}, chunkWorkers)
+	)
+	for i, chunk := range chunks {
+		wg.Add(1)
+		go func(i int, chunk string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			if Verbose {
+				fmt.Fprintf(statusOut, "🧩 Analyzing part %d/%d (~%d tokens)\n", i+1, len(chunks), estimateTokens(chunk))
+			}
+			prompt := fmt.Sprintf("Task: %s\n\nPart %d of %d:\n---\n%s\n---", task, i+1, len(chunks), chunk)
+			resp, err := complete(ctx, client, openai.ChatCompletionRequest{
+				Model: Model,
+				Messages: []openai.ChatCompletionMessage{
+					{Role: openai.ChatMessageRoleSystem, Content: chunkSystemPrompt},
+					{Role: openai.ChatMessageRoleUser, Content: prompt},
+				},
+				MaxTokens: MaxTokens,
+			}, io.Discard)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = err
+				}
+				return
+			}
+			usage = addUsage(usage, resp.Usage)
+			if len(resp.Choices) > 0 {
+				notes[i] = resp.Choices[0].Message.Content
+			}
+		}(i, chunk)
+	}
+	wg.Wait()
+	return notes, usage, firstErr
+}
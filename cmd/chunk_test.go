@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestSplitChunks(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		maxChars int
+		want     []string
+	}{
+		{"fits", "a\nb\n", 10, []string{"a\nb\n"}},
+		{"split at lines", "aaa\nbbb\nccc\n", 8, []string{"aaa\nbbb\n", "ccc\n"}},
+		{"long line", "ab\ncdefghij\n", 4, []string{"ab\n", "cdef", "ghij", "\n"}},
+		{"zero budget", "aaa\nbbb\n", 0, []string{"aaa\nbbb\n"}},
+		{"negative budget", "aaa\nbbb\n", -512, []string{"aaa\nbbb\n"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := splitChunks(tt.text, tt.maxChars); !slices.Equal(got, tt.want) {
+				t.Errorf("splitChunks = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// setModelLimits, model ayarlarını test boyunca değiştirir.
+func setModelLimits(t *testing.T, contextSize, maxTokens int) {
+	t.Helper()
+	previousContext, previousMax, previousModel := ContextSize, MaxTokens, Model
+	ContextSize, MaxTokens, Model = contextSize, maxTokens, "test-model"
+	t.Cleanup(func() { ContextSize, MaxTokens, Model = previousContext, previousMax, previousModel })
+}
+
+func TestCondenseWithoutRoomForData(t *testing.T) {
+	setModelLimits(t, 8192, 1024)
+	quietStatus(t)
+	data := strings.Repeat("line of a large log\n", 5000)
+
+	// Veri sığıyorsa istemci hiç kullanılmaz
+	small, _, err := condense(context.Background(), nil, "system", "short input", "why?")
+	if err != nil || small != "short input" {
+		t.Errorf("small input: %q, %v", small, err)
+	}
+
+	// Sorunun kendisi bütçeyi dolduruyorsa bölmeden usage hatası döner
+	task := strings.Repeat("why is this failing? ", 1000)
+	_, _, err = condense(context.Background(), nil, "system", data, task)
+	if ExitCode(err) != ExitUsage || !strings.Contains(err.Error(), "leaves no room for input data") {
+		t.Errorf("err = %v, want a usage error about the question size", err)
+	}
+}
+
+func TestInputBudget(t *testing.T) {
+	tests := []struct {
+		name        string
+		contextSize int
+		maxTokens   int
+		want        int
+	}{
+		{"large window", 128000, 1024, 128000 - 1024 - 4 - contextReserve},
+		{"window barely fits", 4096, 1024, 4096 - 1024 - 4 - contextReserve},
+		{"window smaller than the reserve", 2048, 1024, 0},
+		{"no floor above the window", 3500, 1024, 3500 - 1024 - 4 - contextReserve},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setModelLimits(t, tt.contextSize, tt.maxTokens)
+			if got := inputBudget("system prompt"); got != tt.want {
+				t.Errorf("inputBudget = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCondenseLimits(t *testing.T) {
+	quietStatus(t)
+	tests := []struct {
+		name        string
+		contextSize int
+		maxTokens   int
+		data        string
+		want        string
+	}{
+		{
+			name:        "window too small for any chunk",
+			contextSize: 3000,
+			maxTokens:   1024,
+			data:        strings.Repeat("line of a large log\n", 500),
+			want:        "leaves no room for input data after --max-tokens",
+		},
+		{
+			name:        "too many chunk calls",
+			contextSize: 8192,
+			maxTokens:   1024,
+			data:        strings.Repeat("line of a large log\n", 100000),
+			want:        "model calls",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setModelLimits(t, tt.contextSize, tt.maxTokens)
+			// İstemci nil'dir: hata herhangi bir istekten önce dönmelidir
+			_, _, err := condense(context.Background(), nil, "system", tt.data, "why?")
+			if ExitCode(err) != ExitUsage || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("err = %v, want a usage error containing %q", err, tt.want)
+			}
+		})
+	}
+}
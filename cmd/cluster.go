@@ -0,0 +1,425 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/duration"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/restmapper"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/yaml"
+)
+
+// Sunucu taraflı apply'da alanların sahibi olarak görünen isim.
+const fieldManager = "kube-ai"
+
+// Cluster, kube-ai'ın Kubernetes API erişim katmanıdır. Tüm komutlar kubectl
+// yerine bunu kullanır. Testlerde NewClusterFromClients ile fake clientset
+// (k8s.io/client-go/kubernetes/fake, k8s.io/client-go/dynamic/fake) verilebilir.
+type Cluster struct {
+	Clientset kubernetes.Interface
+	Dynamic   dynamic.Interface
+	Mapper    meta.RESTMapper
+	// Namespace, --namespace veya kubeconfig context'inden çözülen varsayılan namespace.
+	Namespace string
+}
+
+// NewCluster, --kubeconfig, --context ve --namespace global flag'lerine göre
+// bir Cluster oluşturur. Flag verilmezse KUBECONFIG ve ~/.kube/config kullanılır.
+func NewCluster() (*Cluster, error) {
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	rules.ExplicitPath = Kubeconfig
+	overrides := &clientcmd.ConfigOverrides{CurrentContext: KubeContext}
+	overrides.Context.Namespace = KubeNamespace
+
+	clientConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules, overrides)
+	restConfig, err := clientConfig.ClientConfig()
+	if err != nil {
+		return nil, clusterErrorf("failed to load kubeconfig: %w", err)
+	}
+	restConfig.UserAgent = "kube-ai/" + VERSION
+
+	namespace, _, err := clientConfig.Namespace()
+	if err != nil {
+		return nil, clusterErrorf("failed to resolve namespace from kubeconfig: %w", err)
+	}
+
+	clientset, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return nil, clusterErrorf("failed to create Kubernetes client: %w", err)
+	}
+	dyn, err := dynamic.NewForConfig(restConfig)
+	if err != nil {
+		return nil, clusterErrorf("failed to create Kubernetes client: %w", err)
+	}
+
+	discovery := clientset.Discovery()
+	mapper := restmapper.NewShortcutExpander(
+		restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(discovery)),
+		discovery,
+		func(string) {},
+	)
+	return NewClusterFromClients(clientset, dyn, mapper, namespace), nil
+}
+
+// NewClusterFromClients, hazır istemcilerden bir Cluster oluşturur.
+func NewClusterFromClients(clientset kubernetes.Interface, dyn dynamic.Interface, mapper meta.RESTMapper, namespace string) *Cluster {
+	if namespace == "" {
+		namespace = metav1.NamespaceDefault
+	}
+	return &Cluster{Clientset: clientset, Dynamic: dyn, Mapper: mapper, Namespace: namespace}
+}
+
+// ResolveNamespace, komuta özel --ns verilmişse onu, yoksa varsayılan namespace'i döndürür.
+func (c *Cluster) ResolveNamespace(namespace string) string {
+	if namespace != "" {
+		return namespace
+	}
+	return c.Namespace
+}
+
+// splitResourceName, "deployment/nginx" biçimini tür ve isme ayırır.
+func splitResourceName(resource string) (string, string, error) {
+	parts := strings.SplitN(resource, "/", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", usageErrorf("resource must be in type/name form (e.g. pod/nginx), got %q", resource)
+	}
+	return parts[0], parts[1], nil
+}
+
+// mapping, "deploy", "deployment", "deployments.apps" gibi bir tür adını REST eşlemesine çevirir.
+func (c *Cluster) mapping(resource string) (*meta.RESTMapping, error) {
+	gvr, err := c.Mapper.ResourceFor(schema.ParseGroupResource(strings.ToLower(resource)).WithVersion(""))
+	if err != nil {
+		return nil, clusterErrorf("unknown resource type %q: %w", resource, err)
+	}
+	gvk, err := c.Mapper.KindFor(gvr)
+	if err != nil {
+		return nil, clusterErrorf("unknown resource type %q: %w", resource, err)
+	}
+	mapping, err := c.Mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return nil, clusterErrorf("unknown resource type %q: %w", resource, err)
+	}
+	return mapping, nil
+}
+
+// resourceClient, eşlemenin kapsamına göre (namespaced veya cluster-wide) dinamik istemci döndürür.
+func (c *Cluster) resourceClient(mapping *meta.RESTMapping, namespace string) dynamic.ResourceInterface {
+	if mapping.Scope.Name() == meta.RESTScopeNameRoot {
+		return c.Dynamic.Resource(mapping.Resource)
+	}
+	return c.Dynamic.Resource(mapping.Resource).Namespace(c.ResolveNamespace(namespace))
+}
+
+// Get, bir kaynağı tür ve isme göre okur.
+func (c *Cluster) Get(ctx context.Context, resource, name, namespace string) (*unstructured.Unstructured, error) {
+	mapping, err := c.mapping(resource)
+	if err != nil {
+		return nil, err
+	}
+	obj, err := c.resourceClient(mapping, namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, clusterErrorf("failed to get %s/%s: %w", resource, name, err)
+	}
+	// Gürültü alanları atılır; Secret içerikleri ve token'lar modele gönderilmeden önce maskelenir
+	if !Raw {
+		normalizeObject(obj.Object)
+	}
+	redactor().Object(obj.Object, objectRef(obj))
+	return obj, nil
+}
+
+// GetYAML, 'kubectl get <resource> <name> -o yaml' eşdeğeridir.
+func (c *Cluster) GetYAML(ctx context.Context, resource, name, namespace string) (string, error) {
+	obj, err := c.Get(ctx, resource, name, namespace)
+	if err != nil {
+		return "", err
+	}
+	out, err := yaml.Marshal(obj.Object)
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+// List, bir türdeki kaynakları kısa bir tablo olarak listeler. Pod'lar için
+// kubectl'deki gibi READY/STATUS/RESTARTS sütunları gösterilir.
+func (c *Cluster) List(ctx context.Context, resource, namespace string) (string, error) {
+	mapping, err := c.mapping(resource)
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	w := tabwriter.NewWriter(&buf, 0, 4, 2, ' ', 0)
+	if mapping.Resource.Group == "" && mapping.Resource.Resource == "pods" {
+		pods, err := c.Clientset.CoreV1().Pods(c.ResolveNamespace(namespace)).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return "", clusterErrorf("failed to list pods: %w", err)
+		}
+		fmt.Fprintln(w, "NAME\tREADY\tSTATUS\tRESTARTS\tAGE\tNODE")
+		for i := range pods.Items {
+			pod := &pods.Items[i]
+			ready, total, restarts := podContainerCounts(pod)
+			fmt.Fprintf(w, "%s\t%d/%d\t%s\t%d\t%s\t%s\n", pod.Name, ready, total, podStatus(pod), restarts, age(pod.CreationTimestamp), pod.Spec.NodeName)
+		}
+	} else {
+		list, err := c.resourceClient(mapping, namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return "", clusterErrorf("failed to list %s: %w", resource, err)
+		}
+		fmt.Fprintln(w, "NAME\tAGE")
+		for _, item := range list.Items {
+			fmt.Fprintf(w, "%s\t%s\n", item.GetName(), age(item.GetCreationTimestamp()))
+		}
+	}
+	w.Flush()
+	return buf.String(), nil
+}
+
+// Describe, 'kubectl describe' yerine kaynağın spec/status'unu ve ilgili event'leri döndürür.
+func (c *Cluster) Describe(ctx context.Context, resource, name, namespace string) (string, error) {
+	obj, err := c.Get(ctx, resource, name, namespace)
+	if err != nil {
+		return "", err
+	}
+	out, err := yaml.Marshal(obj.Object)
+	if err != nil {
+		return "", err
+	}
+
+	events := "<none>\n"
+	if obj.GetNamespace() != "" {
+		if events, err = c.Events(ctx, obj.GetNamespace(), name); err != nil {
+			return "", err
+		}
+	}
+	return fmt.Sprintf("%s\nEvents:\n%s", out, events), nil
+}
+
+// Events, namespace'deki event'leri zamana göre sıralı listeler. name verilirse
+// yalnızca o nesneyi ilgilendiren event'ler döndürülür.
+func (c *Cluster) Events(ctx context.Context, namespace, name string) (string, error) {
+	opts := metav1.ListOptions{}
+	if name != "" {
+		opts.FieldSelector = "involvedObject.name=" + name
+	}
+	list, err := c.Clientset.CoreV1().Events(c.ResolveNamespace(namespace)).List(ctx, opts)
+	if err != nil {
+		return "", clusterErrorf("failed to list events: %w", err)
+	}
+	if len(list.Items) == 0 {
+		return "<none>\n", nil
+	}
+
+	events := list.Items
+	sort.SliceStable(events, func(i, j int) bool {
+		return eventTime(&events[i]).Before(eventTime(&events[j]))
+	})
+
+	var buf bytes.Buffer
+	w := tabwriter.NewWriter(&buf, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "LAST SEEN\tTYPE\tREASON\tOBJECT\tCOUNT\tMESSAGE")
+	for i := range events {
+		e := &events[i]
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s/%s\t%d\t%s\n",
+			duration.HumanDuration(time.Since(eventTime(e))), e.Type, e.Reason,
+			strings.ToLower(e.InvolvedObject.Kind), e.InvolvedObject.Name, e.Count, strings.TrimSpace(e.Message))
+	}
+	w.Flush()
+	return buf.String(), nil
+}
+
+// eventTime, event'in en son görülme zamanını döndürür.
+func eventTime(e *corev1.Event) time.Time {
+	switch {
+	case !e.LastTimestamp.IsZero():
+		return e.LastTimestamp.Time
+	case !e.EventTime.IsZero():
+		return e.EventTime.Time
+	case !e.FirstTimestamp.IsZero():
+		return e.FirstTimestamp.Time
+	}
+	return e.CreationTimestamp.Time
+}
+
+// Logs, 'kubectl logs' eşdeğeridir. tail <= 0 ise tüm log okunur.
+func (c *Cluster) Logs(ctx context.Context, pod, namespace, container string, previous bool, tail int64) (string, error) {
+	opts := &corev1.PodLogOptions{Container: container, Previous: previous}
+	if tail > 0 {
+		opts.TailLines = &tail
+	}
+	return c.podLogs(ctx, pod, namespace, opts)
+}
+
+func (c *Cluster) podLogs(ctx context.Context, pod, namespace string, opts *corev1.PodLogOptions) (string, error) {
+	out, err := c.Clientset.CoreV1().Pods(c.ResolveNamespace(namespace)).GetLogs(pod, opts).DoRaw(ctx)
+	if err != nil {
+		return "", clusterErrorf("failed to get logs for pod %s: %w", pod, err)
+	}
+	return redactor().Text(string(out), "pod/"+pod+" logs"), nil
+}
+
+var (
+	podMetricsGVR  = schema.GroupVersionResource{Group: "metrics.k8s.io", Version: "v1beta1", Resource: "pods"}
+	nodeMetricsGVR = schema.GroupVersionResource{Group: "metrics.k8s.io", Version: "v1beta1", Resource: "nodes"}
+)
+
+// Top, metrics-server'dan pod veya node CPU/bellek kullanımını okur ('kubectl top' eşdeğeri).
+func (c *Cluster) Top(ctx context.Context, resource, name, namespace string) (string, error) {
+	var client dynamic.ResourceInterface
+	switch resource {
+	case "pods", "pod":
+		client = c.Dynamic.Resource(podMetricsGVR).Namespace(c.ResolveNamespace(namespace))
+	case "nodes", "node":
+		client = c.Dynamic.Resource(nodeMetricsGVR)
+	default:
+		return "", usageErrorf("resource must be pods or nodes")
+	}
+
+	var items []unstructured.Unstructured
+	if name != "" {
+		obj, err := client.Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return "", clusterErrorf("failed to read metrics (is metrics-server installed?): %w", err)
+		}
+		items = append(items, *obj)
+	} else {
+		list, err := client.List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return "", clusterErrorf("failed to read metrics (is metrics-server installed?): %w", err)
+		}
+		items = list.Items
+	}
+
+	var buf bytes.Buffer
+	w := tabwriter.NewWriter(&buf, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tCONTAINER\tCPU\tMEMORY")
+	for _, item := range items {
+		if usage, ok, _ := unstructured.NestedStringMap(item.Object, "usage"); ok {
+			fmt.Fprintf(w, "%s\t-\t%s\t%s\n", item.GetName(), usage["cpu"], usage["memory"])
+		}
+		containers, _, _ := unstructured.NestedSlice(item.Object, "containers")
+		for _, raw := range containers {
+			container, ok := raw.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			usage, _, _ := unstructured.NestedStringMap(container, "usage")
+			fmt.Fprintf(w, "%s\t%v\t%s\t%s\n", item.GetName(), container["name"], usage["cpu"], usage["memory"])
+		}
+	}
+	w.Flush()
+	return buf.String(), nil
+}
+
+// Apply, çok dokümanlı bir manifest'i sunucu taraflı apply ile uygular ve
+// her kaynak için "kind/name" sonuç satırı döndürür. force false ise başka bir
+// field manager'ın (Helm, ArgoCD, HPA gibi) sahip olduğu alanlar değiştirilmez
+// ve çakışma hata olarak döner; kubectl apply --server-side ile aynı davranış.
+func (c *Cluster) Apply(ctx context.Context, manifest []byte, force bool) ([]string, error) {
+	decoder := utilyaml.NewYAMLOrJSONDecoder(bytes.NewReader(manifest), 4096)
+
+	var results []string
+	for {
+		var obj unstructured.Unstructured
+		if err := decoder.Decode(&obj.Object); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return results, usageErrorf("failed to parse manifest: %w", err)
+		}
+		if len(obj.Object) == 0 {
+			continue
+		}
+
+		gvk := obj.GroupVersionKind()
+		mapping, err := c.Mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+		if err != nil {
+			return results, clusterErrorf("unknown kind %s: %w", gvk.Kind, err)
+		}
+
+		data, err := obj.MarshalJSON()
+		if err != nil {
+			return results, err
+		}
+		_, err = c.resourceClient(mapping, obj.GetNamespace()).Patch(ctx, obj.GetName(), types.ApplyPatchType, data,
+			metav1.PatchOptions{FieldManager: fieldManager, Force: &force})
+		if apierrors.IsConflict(err) {
+			return results, clusterErrorf("failed to apply %s/%s: %w (another field manager such as Helm, ArgoCD or an HPA owns these fields; change them there, or re-run with --force-conflicts to take them over)",
+				strings.ToLower(gvk.Kind), obj.GetName(), err)
+		}
+		if err != nil {
+			return results, clusterErrorf("failed to apply %s/%s: %w", strings.ToLower(gvk.Kind), obj.GetName(), err)
+		}
+		results = append(results, fmt.Sprintf("%s/%s", strings.ToLower(gvk.Kind), obj.GetName()))
+	}
+	return results, nil
+}
+
+// podContainerCounts, hazır/toplam container sayısını ve toplam restart sayısını döndürür.
+func podContainerCounts(pod *corev1.Pod) (int, int, int32) {
+	ready, restarts := 0, int32(0)
+	for _, cs := range pod.Status.ContainerStatuses {
+		if cs.Ready {
+			ready++
+		}
+		restarts += cs.RestartCount
+	}
+	return ready, len(pod.Spec.Containers), restarts
+}
+
+// podStatus, kubectl'in STATUS sütunundaki gibi pod'un durumunu özetler
+// (ör. CrashLoopBackOff, ImagePullBackOff, OOMKilled, Evicted, Pending).
+func podStatus(pod *corev1.Pod) string {
+	if pod.DeletionTimestamp != nil {
+		return "Terminating"
+	}
+	reason := string(pod.Status.Phase)
+	if pod.Status.Reason != "" {
+		reason = pod.Status.Reason
+	}
+	for _, cs := range pod.Status.InitContainerStatuses {
+		if cs.State.Waiting != nil && cs.State.Waiting.Reason != "" && cs.State.Waiting.Reason != "PodInitializing" {
+			return "Init:" + cs.State.Waiting.Reason
+		}
+		if cs.State.Terminated != nil && cs.State.Terminated.ExitCode != 0 {
+			return "Init:" + cs.State.Terminated.Reason
+		}
+	}
+	for _, cs := range pod.Status.ContainerStatuses {
+		switch {
+		case cs.State.Waiting != nil && cs.State.Waiting.Reason != "":
+			reason = cs.State.Waiting.Reason
+		case cs.State.Terminated != nil && cs.State.Terminated.Reason != "":
+			reason = cs.State.Terminated.Reason
+		}
+	}
+	return reason
+}
+
+func age(t metav1.Time) string {
+	if t.IsZero() {
+		return "<unknown>"
+	}
+	return duration.HumanDuration(time.Since(t.Time))
+}
@@ -0,0 +1,196 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/kubernetes/scheme"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// newTestCluster, sahte clientset ve dinamik istemciyle bir Cluster kurar.
+func newTestCluster(t *testing.T, objects ...runtime.Object) (*Cluster, *dynamicfake.FakeDynamicClient) {
+	t.Helper()
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "Pod"}, meta.RESTScopeNamespace)
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}, meta.RESTScopeNamespace)
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "Namespace"}, meta.RESTScopeRoot)
+	mapper.Add(schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}, meta.RESTScopeNamespace)
+
+	dyn := dynamicfake.NewSimpleDynamicClient(scheme.Scheme, objects...)
+	return NewClusterFromClients(fake.NewSimpleClientset(objects...), dyn, mapper, ""), dyn
+}
+
+func TestNewClusterFromClientsNamespace(t *testing.T) {
+	cluster, _ := newTestCluster(t)
+	if cluster.Namespace != "default" {
+		t.Errorf("Namespace = %q, want default", cluster.Namespace)
+	}
+	if got := cluster.ResolveNamespace("prod"); got != "prod" {
+		t.Errorf("ResolveNamespace(prod) = %q", got)
+	}
+	if got := cluster.ResolveNamespace(""); got != "default" {
+		t.Errorf("ResolveNamespace(\"\") = %q", got)
+	}
+}
+
+func TestClusterGetYAML(t *testing.T) {
+	cluster, _ := newTestCluster(t, &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "settings", Namespace: "app"},
+		Data:       map[string]string{"mode": "fast"},
+	})
+
+	out, err := cluster.GetYAML(context.Background(), "configmaps", "settings", "app")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"name: settings", "mode: fast"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("GetYAML output has no %q:\n%s", want, out)
+		}
+	}
+
+	_, err = cluster.GetYAML(context.Background(), "configmaps", "missing", "app")
+	if ExitCode(err) != ExitCluster {
+		t.Errorf("missing object: exit code %d, want %d (err %v)", ExitCode(err), ExitCluster, err)
+	}
+	_, err = cluster.GetYAML(context.Background(), "widgets", "x", "app")
+	if ExitCode(err) != ExitCluster {
+		t.Errorf("unknown type: exit code %d, want %d (err %v)", ExitCode(err), ExitCluster, err)
+	}
+}
+
+func TestClusterListPods(t *testing.T) {
+	cluster, _ := newTestCluster(t, &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "web-1", Namespace: "default"},
+		Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "web"}}},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodRunning,
+			ContainerStatuses: []corev1.ContainerStatus{{
+				Name:         "web",
+				RestartCount: 4,
+				State:        corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+			}},
+		},
+	})
+
+	out, err := cluster.List(context.Background(), "pods", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("List output has %d lines, want header and one pod:\n%s", len(lines), out)
+	}
+	fields := strings.Fields(lines[1])
+	if fields[0] != "web-1" || fields[1] != "0/1" || fields[2] != "CrashLoopBackOff" || fields[3] != "4" {
+		t.Errorf("pod row = %q", lines[1])
+	}
+}
+
+const applyManifest = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: settings
+data:
+  mode: fast
+---
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: team-a
+`
+
+func TestClusterApply(t *testing.T) {
+	cluster, dyn := newTestCluster(t)
+	// Sahte object tracker apply ile nesne oluşturmaz; gönderilen nesne döndürülür
+	dyn.PrependReactor("patch", "*", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		obj := &unstructured.Unstructured{}
+		err := obj.UnmarshalJSON(action.(k8stesting.PatchAction).GetPatch())
+		return true, obj, err
+	})
+
+	applied, err := cluster.Apply(context.Background(), []byte(applyManifest), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"configmap/settings", "namespace/team-a"}; strings.Join(applied, ",") != strings.Join(want, ",") {
+		t.Errorf("applied = %v, want %v", applied, want)
+	}
+
+	var patches []k8stesting.PatchAction
+	for _, action := range dyn.Actions() {
+		if patch, ok := action.(k8stesting.PatchAction); ok {
+			patches = append(patches, patch)
+		}
+	}
+	if len(patches) != 2 {
+		t.Fatalf("got %d patch actions, want 2", len(patches))
+	}
+	// Namespace'i olmayan namespaced kaynak varsayılan namespace'e, Namespace cluster kapsamına gider
+	if ns := patches[0].GetNamespace(); ns != "default" {
+		t.Errorf("configmap applied in namespace %q, want default", ns)
+	}
+	if ns := patches[1].GetNamespace(); ns != "" {
+		t.Errorf("namespace applied in namespace %q, want cluster scope", ns)
+	}
+	if patches[0].GetPatchType() != types.ApplyPatchType {
+		t.Errorf("patch type = %s, want server-side apply", patches[0].GetPatchType())
+	}
+}
+
+func TestClusterApplyConflict(t *testing.T) {
+	cluster, dyn := newTestCluster(t)
+	dyn.PrependReactor("patch", "configmaps", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewConflict(schema.GroupResource{Resource: "configmaps"}, "settings",
+			errors.New(`Apply failed with 1 conflict: conflict with "helm": .data.mode`))
+	})
+
+	applied, err := cluster.Apply(context.Background(), []byte(applyManifest), false)
+	if err == nil {
+		t.Fatal("Apply succeeded despite a field manager conflict")
+	}
+	if len(applied) != 0 {
+		t.Errorf("applied = %v, want nothing before the conflict", applied)
+	}
+	if ExitCode(err) != ExitCluster {
+		t.Errorf("exit code %d, want %d", ExitCode(err), ExitCluster)
+	}
+	for _, want := range []string{"configmap/settings", "helm", "--force-conflicts"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not mention %q", err, want)
+		}
+	}
+}
+
+func TestClusterApplyErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		manifest string
+		exitCode int
+	}{
+		{"unknown kind", "apiVersion: example.com/v1\nkind: Widget\nmetadata:\n  name: w\n", ExitCluster},
+		{"invalid yaml", "kind: ConfigMap\nmetadata: [\n", ExitUsage},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cluster, _ := newTestCluster(t)
+			_, err := cluster.Apply(context.Background(), []byte(tt.manifest), false)
+			if ExitCode(err) != tt.exitCode {
+				t.Errorf("exit code %d, want %d (err %v)", ExitCode(err), tt.exitCode, err)
+			}
+		})
+	}
+}
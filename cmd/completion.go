@@ -22,19 +22,18 @@ PowerShell:
   kube-ai completion powershell | Out-String | Invoke-Expression
 `,
 	Args: cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		switch args[0] {
 		case "bash":
-			_ = RootCmd.GenBashCompletion(cmd.OutOrStdout())
+			return RootCmd.GenBashCompletion(cmd.OutOrStdout())
 		case "zsh":
-			_ = RootCmd.GenZshCompletion(cmd.OutOrStdout())
+			return RootCmd.GenZshCompletion(cmd.OutOrStdout())
 		case "fish":
-			_ = RootCmd.GenFishCompletion(cmd.OutOrStdout(), true)
+			return RootCmd.GenFishCompletion(cmd.OutOrStdout(), true)
 		case "powershell":
-			_ = RootCmd.GenPowerShellCompletion(cmd.OutOrStdout())
-		default:
-			cmd.Print("Shell not supported. Use: bash|zsh|fish|powershell")
+			return RootCmd.GenPowerShellCompletion(cmd.OutOrStdout())
 		}
+		return usageErrorf("shell not supported. Use: bash|zsh|fish|powershell")
 	},
 }
 
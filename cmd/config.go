@@ -0,0 +1,438 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"sigs.k8s.io/yaml"
+)
+
+// Config dosyasında profil düzeyinde ayarlanabilen global flag'ler. Her biri
+// KUBE_AI_<AD> ortam değişkeniyle de verilebilir (ör. KUBE_AI_MAX_TOKENS).
+// Öncelik: flag > ortam değişkeni > profil (komuta özel ayar önce) > varsayılan.
+var profileSettings = []string{
+	"provider", "model", "max-tokens", "base-url", "api-version",
+	"kubeconfig", "context", "namespace",
+	"timeout", "max-retries", "max-iterations", "context-size",
+	"count-tokens", "no-stream", "show-redacted", "raw", "output",
+}
+
+// Çıktı biçimi seçen flag'lerde bulunan annotation; profildeki "output" ayarı
+// yalnızca bu flag'lere uygulanır (generate'in --output'u bir dosya adıdır).
+const formatFlagAnnotation = "kube-ai/format"
+
+const defaultProfile = "default"
+
+// Profile, config dosyasındaki bir profildir.
+type Profile struct {
+	// Settings, profileSettings'teki anahtarların değerleridir.
+	Settings map[string]interface{}
+	// Redact, dahili ve redact.yaml kurallarına eklenen redaction kurallarıdır.
+	Redact RedactConfig
+	// Commands, komuta özel flag değerleridir (ör. commands.audit.model).
+	Commands map[string]map[string]interface{}
+}
+
+// configFile, config dosyasının yolunu döndürür.
+func configFile() string {
+	if path := os.Getenv("KUBE_AI_CONFIG"); path != "" {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".config", "kube-ai", "config.yaml")
+}
+
+// readConfig, config dosyasını ham haliyle okur; dosya yoksa boş döner.
+func readConfig() (map[string]interface{}, error) {
+	config := map[string]interface{}{}
+	path := configFile()
+	if path == "" {
+		return config, nil
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return config, nil
+	}
+	if err != nil {
+		return nil, usageErrorf("failed to read config file %s: %w", path, err)
+	}
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, usageErrorf("failed to parse config file %s: %w", path, err)
+	}
+	if config == nil {
+		config = map[string]interface{}{}
+	}
+	return config, nil
+}
+
+// writeConfig, config dosyasını yazar. API uç noktaları içerebileceği için
+// yalnızca kullanıcının okuyabileceği izinlerle oluşturulur.
+func writeConfig(config map[string]interface{}) error {
+	path := configFile()
+	if path == "" {
+		return fmt.Errorf("could not determine the config file path")
+	}
+	out, err := yaml.Marshal(config)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	return os.WriteFile(path, out, 0600)
+}
+
+// activeProfileName, --profile, KUBE_AI_PROFILE, current-profile sırasıyla seçilen profildir.
+func activeProfileName(config map[string]interface{}) string {
+	if ProfileName != "" {
+		return ProfileName
+	}
+	if name := os.Getenv("KUBE_AI_PROFILE"); name != "" {
+		return name
+	}
+	if name, ok := config["current-profile"].(string); ok && name != "" {
+		return name
+	}
+	return defaultProfile
+}
+
+// profileMap, config içindeki profilin ham haritasını döndürür; create true ise yoksa oluşturur.
+func profileMap(config map[string]interface{}, name string, create bool) map[string]interface{} {
+	profiles, ok := config["profiles"].(map[string]interface{})
+	if !ok {
+		if !create {
+			return nil
+		}
+		profiles = map[string]interface{}{}
+		config["profiles"] = profiles
+	}
+	profile, ok := profiles[name].(map[string]interface{})
+	if !ok && create {
+		profile = map[string]interface{}{}
+		profiles[name] = profile
+	}
+	return profile
+}
+
+// parseProfile, ham profil haritasını Profile'a çevirir.
+func parseProfile(raw map[string]interface{}) (*Profile, error) {
+	profile := &Profile{Settings: map[string]interface{}{}, Commands: map[string]map[string]interface{}{}}
+	for key, value := range raw {
+		switch key {
+		case "redact":
+			data, err := yaml.Marshal(value)
+			if err != nil {
+				return nil, err
+			}
+			if err := yaml.Unmarshal(data, &profile.Redact); err != nil {
+				return nil, fmt.Errorf("invalid redact rules: %w", err)
+			}
+		case "commands":
+			commands, ok := value.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("commands must be a map of command name to settings")
+			}
+			for name, settings := range commands {
+				m, ok := settings.(map[string]interface{})
+				if !ok {
+					return nil, fmt.Errorf("commands.%s must be a map of flag name to value", name)
+				}
+				profile.Commands[name] = m
+			}
+		default:
+			if !isProfileSetting(key) {
+				return nil, fmt.Errorf("unknown setting %q (valid: %s, redact, commands)", key, strings.Join(profileSettings, ", "))
+			}
+			profile.Settings[key] = value
+		}
+	}
+	return profile, nil
+}
+
+func isProfileSetting(key string) bool {
+	for _, setting := range profileSettings {
+		if setting == key {
+			return true
+		}
+	}
+	return false
+}
+
+// envName, bir ayarın ortam değişkeni adıdır: max-tokens -> KUBE_AI_MAX_TOKENS.
+func envName(key string) string {
+	return "KUBE_AI_" + strings.ToUpper(strings.ReplaceAll(key, "-", "_"))
+}
+
+// commandKey, komuta özel ayarların anahtarıdır (ör. "audit" veya "config set").
+func commandKey(cmd *cobra.Command) string {
+	return strings.TrimPrefix(cmd.CommandPath(), RootCmd.Name()+" ")
+}
+
+// applyConfig, komut çalışmadan önce kullanıcının vermediği flag'leri ortam
+// değişkenlerinden ve aktif profilden doldurur.
+func applyConfig(cmd *cobra.Command) error {
+	config, err := readConfig()
+	if err != nil {
+		return err
+	}
+	name := activeProfileName(config)
+	raw := profileMap(config, name, false)
+	if raw == nil && name != defaultProfile && (ProfileName != "" || os.Getenv("KUBE_AI_PROFILE") != "") {
+		return usageErrorf("profile %q not found in %s", name, configFile())
+	}
+	profile, err := parseProfile(raw)
+	if err != nil {
+		return usageErrorf("invalid profile %q in %s: %w", name, configFile(), err)
+	}
+	profileRedact = profile.Redact
+
+	// Ortam değişkenleri ve profil ayarları. Ortam değişkeninden gelen flag'ler
+	// Changed işaretlenmez (kullanıcı flag'i vermemiştir); ayrıca izlenir.
+	fromEnv := make(map[string]bool)
+	for _, key := range profileSettings {
+		flag := RootCmd.PersistentFlags().Lookup(key)
+		if key == "output" {
+			flag = cmd.Flags().Lookup(key)
+			if flag == nil || flag.Annotations[formatFlagAnnotation] == nil {
+				continue
+			}
+		}
+		if flag == nil || flag.Changed {
+			continue
+		}
+		if value, ok := os.LookupEnv(envName(key)); ok && value != "" {
+			if err := flag.Value.Set(value); err != nil {
+				return usageErrorf("invalid value %q for %s: %w", value, envName(key), err)
+			}
+			// Komuta özel profil ayarı ortam değişkenini ezmesin
+			fromEnv[flag.Name] = true
+			continue
+		}
+		if overrides, ok := profile.Commands[commandKey(cmd)]; ok {
+			if _, ok := overrides[key]; ok {
+				continue // komuta özel değer aşağıda uygulanır
+			}
+		}
+		if value, ok := profile.Settings[key]; ok {
+			if err := setFlag(flag, value); err != nil {
+				return usageErrorf("invalid value for %s in profile %q: %w", key, name, err)
+			}
+		}
+	}
+
+	// Komuta özel ayarlar komutun herhangi bir flag'ini ayarlayabilir
+	for key, value := range profile.Commands[commandKey(cmd)] {
+		flag := cmd.Flags().Lookup(key)
+		if flag == nil {
+			return usageErrorf("unknown flag %q in commands.%s of profile %q", key, commandKey(cmd), name)
+		}
+		if flag.Changed || fromEnv[flag.Name] {
+			continue
+		}
+		if err := setFlag(flag, value); err != nil {
+			return usageErrorf("invalid value for commands.%s.%s in profile %q: %w", commandKey(cmd), key, name, err)
+		}
+	}
+	return nil
+}
+
+// setFlag, config'den gelen değeri flag'e yazar. Listeler virgülle birleştirilir.
+func setFlag(flag *pflag.Flag, value interface{}) error {
+	if list, ok := value.([]interface{}); ok {
+		items := make([]string, len(list))
+		for i, item := range list {
+			items[i] = fmt.Sprint(item)
+		}
+		return flag.Value.Set(strings.Join(items, ","))
+	}
+	return flag.Value.Set(fmt.Sprint(value))
+}
+
+// splitConfigKey, "model" veya "commands.audit.model" biçimindeki anahtarı böler.
+func splitConfigKey(key string) ([]string, error) {
+	parts := strings.Split(key, ".")
+	switch {
+	case len(parts) == 1 && (key == "current-profile" || isProfileSetting(key)):
+		return parts, nil
+	case len(parts) == 3 && parts[0] == "commands" && parts[1] != "" && parts[2] != "":
+		return parts, nil
+	}
+	return nil, usageErrorf("unknown config key %q (valid: current-profile, %s, commands.<command>.<flag>)", key, strings.Join(profileSettings, ", "))
+}
+
+// checkCommandFlag, commands.<command>.<flag> anahtarındaki komutun ve flag'in
+// var olduğunu doğrular; yoksa kaydedilen ayar o komutu her çalıştırmada bozardı.
+func checkCommandFlag(command, name string) error {
+	found, rest, err := RootCmd.Find(strings.Fields(command))
+	if err != nil || len(rest) > 0 || found == RootCmd || commandKey(found) != strings.Join(strings.Fields(command), " ") {
+		return usageErrorf("unknown command %q in commands.%s.%s", command, command, name)
+	}
+	if found.Flags().Lookup(name) == nil && found.InheritedFlags().Lookup(name) == nil {
+		return usageErrorf("unknown flag %q for command %q (see kube-ai %s --help)", name, command, command)
+	}
+	return nil
+}
+
+var ConfigCmd = &cobra.Command{
+	Use:   "config",
+	Short: "View and edit the kube-ai config file",
+	Long: `View and edit ~/.config/kube-ai/config.yaml (or $KUBE_AI_CONFIG).
+
+The config file holds named profiles. A profile sets defaults for global flags
+(` + strings.Join(profileSettings, ", ") + `),
+extra redaction rules under "redact", and per-command flag values under "commands.<command>".
+The active profile is chosen with --profile, $KUBE_AI_PROFILE or current-profile.
+
+Precedence: flag > environment variable (KUBE_AI_<SETTING>, e.g. KUBE_AI_MAX_TOKENS) > profile > default.`,
+}
+
+var configViewCmd = &cobra.Command{
+	Use:   "view",
+	Short: "Print the config file and the active profile",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		config, err := readConfig()
+		if err != nil {
+			return err
+		}
+		fmt.Printf("# %s (active profile: %s)\n", configFile(), activeProfileName(config))
+		if len(config) == 0 {
+			return nil
+		}
+		out, err := yaml.Marshal(config)
+		if err != nil {
+			return err
+		}
+		fmt.Print(string(out))
+		return nil
+	},
+}
+
+var configGetCmd = &cobra.Command{
+	Use:   "get <key>",
+	Short: "Print a value of the active profile (e.g. model, commands.audit.output)",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		parts, err := splitConfigKey(args[0])
+		if err != nil {
+			return err
+		}
+		config, err := readConfig()
+		if err != nil {
+			return err
+		}
+		if parts[0] == "current-profile" {
+			fmt.Println(activeProfileName(config))
+			return nil
+		}
+
+		var value interface{} = profileMap(config, activeProfileName(config), false)
+		for _, part := range parts {
+			m, ok := value.(map[string]interface{})
+			if !ok {
+				value = nil
+				break
+			}
+			value = m[part]
+		}
+		if value == nil {
+			return usageErrorf("%s is not set in profile %q", args[0], activeProfileName(config))
+		}
+		fmt.Println(value)
+		return nil
+	},
+}
+
+var configSetCmd = &cobra.Command{
+	Use:   "set <key> <value>",
+	Short: "Set a value in the active profile (e.g. model gpt-4.1, commands.audit.output json)",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		parts, err := splitConfigKey(args[0])
+		if err != nil {
+			return err
+		}
+		config, err := readConfig()
+		if err != nil {
+			return err
+		}
+
+		// "4096" veya "true" gibi değerler YAML'daki türleriyle saklanır
+		var value interface{}
+		if err := yaml.Unmarshal([]byte(args[1]), &value); err != nil || value == nil {
+			value = args[1]
+		}
+
+		if parts[0] == "commands" {
+			if err := checkCommandFlag(parts[1], parts[2]); err != nil {
+				return err
+			}
+		}
+
+		if parts[0] == "current-profile" {
+			config["current-profile"] = args[1]
+			profileMap(config, args[1], true)
+			if err := writeConfig(config); err != nil {
+				return fmt.Errorf("failed to write config file %s: %w", configFile(), err)
+			}
+			fmt.Printf("✅ Switched to profile %q (%s)\n", args[1], configFile())
+			return nil
+		}
+
+		target := profileMap(config, activeProfileName(config), true)
+		for _, part := range parts[:len(parts)-1] {
+			next, ok := target[part].(map[string]interface{})
+			if !ok {
+				next = map[string]interface{}{}
+				target[part] = next
+			}
+			target = next
+		}
+		target[parts[len(parts)-1]] = value
+
+		if err := writeConfig(config); err != nil {
+			return fmt.Errorf("failed to write config file %s: %w", configFile(), err)
+		}
+		fmt.Printf("✅ Set %s in profile %q (%s)\n", args[0], activeProfileName(config), configFile())
+		return nil
+	},
+}
+
+var configProfilesCmd = &cobra.Command{
+	Use:   "profiles",
+	Short: "List the profiles in the config file",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		config, err := readConfig()
+		if err != nil {
+			return err
+		}
+		profiles, _ := config["profiles"].(map[string]interface{})
+		names := make([]string, 0, len(profiles))
+		for name := range profiles {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		active := activeProfileName(config)
+		for _, name := range names {
+			marker := " "
+			if name == active {
+				marker = "*"
+			}
+			fmt.Println(marker, name)
+		}
+		return nil
+	},
+}
+
+func init() {
+	ConfigCmd.AddCommand(configViewCmd, configGetCmd, configSetCmd, configProfilesCmd)
+}
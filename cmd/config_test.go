@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestApplyConfigPrecedence(t *testing.T) {
+	const defaultModel = "gpt-4o"
+	tests := []struct {
+		name   string
+		args   []string
+		env    string
+		config string
+		want   string
+	}{
+		{
+			name: "default",
+			want: defaultModel,
+		},
+		{
+			name:   "profile",
+			config: "profiles: {default: {model: from-profile}}",
+			want:   "from-profile",
+		},
+		{
+			name:   "command-specific profile over profile",
+			config: "profiles: {default: {model: from-profile, commands: {precedence: {model: from-command}}}}",
+			want:   "from-command",
+		},
+		{
+			name:   "command-specific profile of another command is ignored",
+			config: "profiles: {default: {model: from-profile, commands: {audit: {model: from-command}}}}",
+			want:   "from-profile",
+		},
+		{
+			name:   "environment over command-specific profile",
+			env:    "from-env",
+			config: "profiles: {default: {model: from-profile, commands: {precedence: {model: from-command}}}}",
+			want:   "from-env",
+		},
+		{
+			name:   "flag over environment",
+			args:   []string{"--model", "from-flag"},
+			env:    "from-env",
+			config: "profiles: {default: {model: from-profile, commands: {precedence: {model: from-command}}}}",
+			want:   "from-flag",
+		},
+		{
+			name:   "flag set to its default value still wins",
+			args:   []string{"-m", defaultModel},
+			env:    "from-env",
+			config: "profiles: {default: {model: from-profile, commands: {precedence: {model: from-command}}}}",
+			want:   defaultModel,
+		},
+		{
+			name:   "current-profile selects the profile",
+			config: "current-profile: work\nprofiles: {default: {model: from-default}, work: {model: from-work}}",
+			want:   "from-work",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config.yaml")
+			if err := os.WriteFile(path, []byte(tt.config), 0600); err != nil {
+				t.Fatal(err)
+			}
+			t.Setenv("KUBE_AI_CONFIG", path)
+			t.Setenv("KUBE_AI_PROFILE", "")
+			t.Setenv("KUBE_AI_MODEL", tt.env)
+
+			cmd := &cobra.Command{Use: "precedence", RunE: func(*cobra.Command, []string) error { return nil }}
+			RootCmd.AddCommand(cmd)
+			model := RootCmd.PersistentFlags().Lookup("model")
+			previousRedact := profileRedact
+			t.Cleanup(func() {
+				RootCmd.RemoveCommand(cmd)
+				model.Value.Set(defaultModel)
+				model.Changed = false
+				profileRedact = previousRedact
+			})
+
+			if err := cmd.ParseFlags(tt.args); err != nil {
+				t.Fatal(err)
+			}
+			if err := applyConfig(cmd); err != nil {
+				t.Fatal(err)
+			}
+			if Model != tt.want {
+				t.Errorf("model = %q, want %q", Model, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyConfigErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+		env    map[string]string
+	}{
+		{"unknown profile setting", "profiles: {default: {colour: blue}}", nil},
+		{"unknown command flag", "profiles: {default: {commands: {precedence: {no-such-flag: 1}}}}", nil},
+		{"invalid environment value", "", map[string]string{"KUBE_AI_MAX_TOKENS": "many"}},
+		{"missing profile", "profiles: {default: {}}", map[string]string{"KUBE_AI_PROFILE": "work"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config.yaml")
+			if err := os.WriteFile(path, []byte(tt.config), 0600); err != nil {
+				t.Fatal(err)
+			}
+			t.Setenv("KUBE_AI_CONFIG", path)
+			t.Setenv("KUBE_AI_PROFILE", "")
+			t.Setenv("KUBE_AI_MAX_TOKENS", "")
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+
+			cmd := &cobra.Command{Use: "precedence", RunE: func(*cobra.Command, []string) error { return nil }}
+			RootCmd.AddCommand(cmd)
+			previousRedact := profileRedact
+			t.Cleanup(func() {
+				RootCmd.RemoveCommand(cmd)
+				profileRedact = previousRedact
+			})
+			if err := applyConfig(cmd); ExitCode(err) != ExitUsage {
+				t.Errorf("err = %v, want a usage error", err)
+			}
+		})
+	}
+}
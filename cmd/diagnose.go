@@ -1,13 +1,10 @@
 package cmd
 
 import (
-	"context"
 	"fmt"
 	"os"
-	"os/exec"
 	"strings"
 
-	openai "github.com/sashabaranov/go-openai"
 	"github.com/spf13/cobra"
 )
 
@@ -15,42 +12,66 @@ var (
 	diagnoseInputFile string
 	diagnoseResName   string
 	diagnoseNamespace string
+	diagnoseEvidence  EvidenceOptions
+	diagnoseAll       bool
+	diagnoseWorkers   int
 )
 
 var DiagnoseCmd = &cobra.Command{
 	Use:   "diagnose [resource-type] [resource-name] [flags] [optional: question]",
 	Short: "Diagnose problems in Kubernetes pods using AI",
-	Long:  "Troubleshoot issues in Kubernetes pods by analyzing describe outputs, logs, or manifest configurations with the help of AI.",
-	Run: func(cmd *cobra.Command, args []string) {
-		apiKey := os.Getenv("OPENAI_API_KEY")
-		if apiKey == "" {
-			fmt.Println("❌ OPENAI_API_KEY environment variable not set.")
-			return
+	Long: `Troubleshoot issues in Kubernetes pods by analyzing describe outputs, logs, or manifest configurations with the help of AI.
+
+With --all, every unhealthy pod in the namespace (not ready, crash-looping, Pending or Evicted) is found,
+grouped by owning workload and failure reason, diagnosed concurrently and summarized in a ranked table.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := NewProvider()
+		if err != nil {
+			return err
+		}
+
+		if diagnoseAll {
+			if diagnoseInputFile != "" || diagnoseResName != "" {
+				return usageErrorf("--all cannot be combined with --file or --name")
+			}
+			cluster, err := NewCluster()
+			if err != nil {
+				return err
+			}
+			diagnoseNamespace = cluster.ResolveNamespace(diagnoseNamespace)
+			SaveToHistory("diagnose", fmt.Sprintf("all ns=%s", diagnoseNamespace))
+			return runDiagnoseAll(cmd.Context(), client, cluster, diagnoseNamespace)
 		}
 
 		var diagnoseData string
 		var userQuestion string
+		var cluster *Cluster
 
 		if diagnoseInputFile != "" {
-			content, err := os.ReadFile(diagnoseInputFile)
+			content, err := readInput(diagnoseInputFile)
+			if err != nil {
+				return err
+			}
+			diagnoseData = prepareInput(content, inputName(diagnoseInputFile))
+		} else if diagnoseResName != "" {
+			// namespace verilmediyse -n veya kubeconfig'deki namespace kullanılır
+			resourceType, name, err := splitResourceName(diagnoseResName)
 			if err != nil {
-				fmt.Printf("❌ Failed to read file %s: %v\n", diagnoseInputFile, err)
-				return
+				return err
 			}
-			diagnoseData = string(content)
-		} else if diagnoseResName != "" && diagnoseNamespace != "" {
-			cmd := exec.Command("kubectl", "describe", diagnoseResName, "-n", diagnoseNamespace)
-			output, err := cmd.CombinedOutput()
+			if cluster, err = NewCluster(); err != nil {
+				return err
+			}
+			diagnoseNamespace = cluster.ResolveNamespace(diagnoseNamespace)
+			evidence, err := cluster.CollectEvidence(cmd.Context(), resourceType, name, diagnoseNamespace, diagnoseEvidence)
 			if err != nil {
-				fmt.Printf("❌ Failed to describe resource from cluster: %v\nOutput: %s\n", err, output)
-				return
+				return err
 			}
-			diagnoseData = string(output)
+			diagnoseData = evidence.String()
 		} else if len(args) > 0 {
 			userQuestion = strings.Join(args, " ")
 		} else {
-			fmt.Println("❌ Please provide a file (-f), a resource name (--name and --ns), or a direct question as an argument.")
-			return
+			return usageErrorf("please provide a file (-f), a resource name (--name), or a direct question as an argument")
 		}
 
 		if userQuestion == "" {
@@ -59,47 +80,42 @@ var DiagnoseCmd = &cobra.Command{
 
 		SaveToHistory("diagnose", fmt.Sprintf("name=%s ns=%s file=%s question=%s", diagnoseResName, diagnoseNamespace, diagnoseInputFile, userQuestion))
 
-		fullPrompt := fmt.Sprintf(`Pod Output:
----
-%s
----
-Task: %s
-`, diagnoseData, userQuestion)
-
-		client := openai.NewClient(apiKey)
-		resp, err := client.CreateChatCompletion(
-			context.Background(),
-			openai.ChatCompletionRequest{
-				Model: Model,
-				Messages: []openai.ChatCompletionMessage{
-					{
-						Role: openai.ChatMessageRoleSystem,
-						Content: `You are a Kubernetes troubleshooter.
-Analyze pod outputs such as describe results, logs, and events.
-Identify problems like CrashLoopBackOff, OOMKilled, ImagePullBackOff, readiness probe failures, node pressure, etc.
-Provide a clear diagnosis and suggest potential fixes.`,
-					},
-					{
-						Role:    openai.ChatMessageRoleUser,
-						Content: fullPrompt,
-					},
-				},
-				MaxTokens: MaxTokens,
-			},
-		)
-
+		promptData := PromptData{Resource: diagnoseResName, Namespace: diagnoseNamespace, Question: userQuestion}
+		systemPrompt, err := renderPrompt("diagnose-system", promptData)
+		if err != nil {
+			return err
+		}
+		// Modelin context penceresine sığmayan girdi parça parça özetlenir
+		diagnoseData, condenseUsage, err := condense(cmd.Context(), client, systemPrompt, diagnoseData, userQuestion)
 		if err != nil {
-			fmt.Printf("❌ OpenAI error: %v\n", err)
-			return
+			return err
+		}
+		promptData.Data = diagnoseData
+		fullPrompt, err := renderPrompt("diagnose", promptData)
+		if err != nil {
+			return err
 		}
 
+		// Cluster'daki bir kaynak teşhis ediliyorsa model ek kanıt toplamak için araç çağırabilir
 		fmt.Println("\n🛠️ Diagnosis from AI:")
-		fmt.Println(strings.TrimSpace(resp.Choices[0].Message.Content))
+		resp, evidence, err := investigate(cmd.Context(), client, cluster, systemPrompt, fullPrompt, diagnoseNamespace, os.Stdout)
+		if err != nil {
+			return err
+		}
+		printEvidence(evidence)
+		printRedactions()
+		printUsage(resp.Model, addUsage(resp.Usage, condenseUsage))
+		return nil
 	},
 }
 
 func init() {
-	DiagnoseCmd.Flags().StringVarP(&diagnoseInputFile, "file", "f", "", "Path to a file containing pod describe output or logs")
+	DiagnoseCmd.Flags().StringVarP(&diagnoseInputFile, "file", "f", "", "Path to a file containing pod describe output or logs (- reads stdin)")
 	DiagnoseCmd.Flags().StringVar(&diagnoseResName, "name", "", "Kubernetes resource type/name (e.g., pod/mypod)")
 	DiagnoseCmd.Flags().StringVar(&diagnoseNamespace, "ns", "", "Namespace of the resource")
+	DiagnoseCmd.Flags().DurationVar(&diagnoseEvidence.Since, "since", 0, "Only collect logs newer than this duration (e.g. 30m, 2h)")
+	DiagnoseCmd.Flags().Int64Var(&diagnoseEvidence.Tail, "tail", 100, "Number of log lines to collect per container (0 for all)")
+	DiagnoseCmd.Flags().StringVar(&diagnoseEvidence.Container, "container", "", "Only collect logs of this container")
+	DiagnoseCmd.Flags().BoolVar(&diagnoseAll, "all", false, "Diagnose every unhealthy pod in the namespace, grouped by owner and reason")
+	DiagnoseCmd.Flags().IntVar(&diagnoseWorkers, "workers", 4, "Number of groups diagnosed concurrently with --all")
 }
@@ -0,0 +1,297 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+
+	openai "github.com/sashabaranov/go-openai"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// Başarısızlık nedenlerinin önem sırası; listede olmayanlar "NotReady" ile aynı ağırlıktadır.
+var reasonSeverity = map[string]int{
+	"OOMKilled":                  100,
+	"CrashLoopBackOff":           90,
+	"Error":                      85,
+	"CreateContainerConfigError": 80,
+	"CreateContainerError":       80,
+	"ImagePullBackOff":           75,
+	"ErrImagePull":               75,
+	"InvalidImageName":           75,
+	"Evicted":                    60,
+	"Pending":                    50,
+	"ContainerCreating":          40,
+	"NotReady":                   30,
+}
+
+// issueGroup, aynı sahibe ait ve aynı nedenle başarısız olan pod'lardır.
+type issueGroup struct {
+	Owner    string
+	Reason   string
+	Pods     []*corev1.Pod
+	Restarts int32
+
+	RootCause string
+	Diagnosis string
+	Err       error
+}
+
+func (g *issueGroup) score() int {
+	score, ok := reasonSeverity[g.Reason]
+	if !ok {
+		score = reasonSeverity["NotReady"]
+	}
+	return score
+}
+
+// unhealthyReason, pod sağlıksızsa (hazır değil, crash-loop, Pending, Evicted)
+// kısa bir neden döndürür. Tamamlanmış (Succeeded) pod'lar sağlıklı sayılır.
+func unhealthyReason(pod *corev1.Pod) (string, bool) {
+	if pod.Status.Phase == corev1.PodSucceeded || pod.DeletionTimestamp != nil {
+		return "", false
+	}
+
+	reason := podStatus(pod)
+	switch pod.Status.Phase {
+	case corev1.PodPending, corev1.PodFailed, corev1.PodUnknown:
+		return reason, true
+	}
+	for _, cs := range pod.Status.ContainerStatuses {
+		if cs.State.Waiting != nil && cs.State.Waiting.Reason != "" {
+			return cs.State.Waiting.Reason, true
+		}
+	}
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == corev1.PodReady && cond.Status != corev1.ConditionTrue {
+			if reason == string(corev1.PodRunning) {
+				reason = "NotReady"
+			}
+			return reason, true
+		}
+	}
+	return "", false
+}
+
+// topOwner, pod'un en üstteki controller'ını (ör. deployment/api) bulur.
+// Aynı ReplicaSet'e ait pod'lar için API çağrıları önbellekten karşılanır.
+func (c *Cluster) topOwner(ctx context.Context, pod *corev1.Pod, cache map[string]string) string {
+	ref := metav1.GetControllerOf(pod)
+	if ref == nil {
+		return "pod/" + pod.Name
+	}
+	key := ref.Kind + "/" + ref.Name
+	if owner, ok := cache[key]; ok {
+		return owner
+	}
+
+	owner := strings.ToLower(key)
+	raw, err := runtime.DefaultUnstructuredConverter.ToUnstructured(pod)
+	if err == nil {
+		current := &unstructured.Unstructured{Object: raw}
+		for depth := 0; depth < maxOwnerDepth; depth++ {
+			next, err := c.controllerOf(ctx, current)
+			if err != nil || next == nil {
+				break
+			}
+			owner = objectRef(next)
+			current = next
+		}
+	}
+	cache[key] = owner
+	return owner
+}
+
+// findIssueGroups, namespace'deki sağlıksız pod'ları sahip ve nedene göre gruplar
+// ve önem sırasına göre döndürür.
+func (c *Cluster) findIssueGroups(ctx context.Context, namespace string) ([]*issueGroup, int, error) {
+	list, err := c.Clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, 0, clusterErrorf("failed to list pods in %s: %w", namespace, err)
+	}
+
+	owners := map[string]string{}
+	groups := map[string]*issueGroup{}
+	unhealthy := 0
+	for i := range list.Items {
+		pod := &list.Items[i]
+		reason, ok := unhealthyReason(pod)
+		if !ok {
+			continue
+		}
+		unhealthy++
+
+		owner := c.topOwner(ctx, pod, owners)
+		key := owner + "|" + reason
+		group, ok := groups[key]
+		if !ok {
+			group = &issueGroup{Owner: owner, Reason: reason}
+			groups[key] = group
+		}
+		group.Pods = append(group.Pods, pod)
+		_, _, restarts := podContainerCounts(pod)
+		group.Restarts += restarts
+	}
+
+	ranked := make([]*issueGroup, 0, len(groups))
+	for _, group := range groups {
+		// En çok yeniden başlayan pod grubu temsil etsin
+		sort.SliceStable(group.Pods, func(i, j int) bool {
+			_, _, ri := podContainerCounts(group.Pods[i])
+			_, _, rj := podContainerCounts(group.Pods[j])
+			return ri > rj
+		})
+		ranked = append(ranked, group)
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		a, b := ranked[i], ranked[j]
+		if a.score() != b.score() {
+			return a.score() > b.score()
+		}
+		if len(a.Pods) != len(b.Pods) {
+			return len(a.Pods) > len(b.Pods)
+		}
+		if a.Restarts != b.Restarts {
+			return a.Restarts > b.Restarts
+		}
+		return a.Owner < b.Owner
+	})
+	return ranked, unhealthy, nil
+}
+
+// runDiagnoseAll, namespace'deki tüm sağlıksız pod gruplarını en fazla
+// diagnoseWorkers eşzamanlı istekle teşhis eder ve sıralı bir özet yazdırır.
+func runDiagnoseAll(ctx context.Context, client Provider, cluster *Cluster, namespace string) error {
+	groups, unhealthy, err := cluster.findIssueGroups(ctx, namespace)
+	if err != nil {
+		return err
+	}
+	if len(groups) == 0 {
+		fmt.Printf("✅ No unhealthy pods found in namespace %s.\n", namespace)
+		return nil
+	}
+	fmt.Printf("🚨 Found %d unhealthy pod(s) in %d group(s) in namespace %s. Diagnosing with %d worker(s)...\n",
+		unhealthy, len(groups), namespace, diagnoseWorkers)
+
+	workers := diagnoseWorkers
+	if workers < 1 {
+		workers = 1
+	}
+
+	var (
+		wg    sync.WaitGroup
+		mu    sync.Mutex
+		usage openai.Usage
+		model string
+		sem   = make(chan struct{}, workers)
+	)
+	for _, group := range groups {
+		wg.Add(1)
+		go func(group *issueGroup) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			pod := group.Pods[0]
+			names := make([]string, 0, len(group.Pods))
+			for _, p := range group.Pods {
+				names = append(names, p.Name)
+			}
+
+			evidence, err := cluster.CollectEvidence(ctx, "pods", pod.Name, namespace, diagnoseEvidence)
+			if err != nil {
+				group.Err = err
+				return
+			}
+			promptData := PromptData{
+				Resource:  group.Owner,
+				Namespace: namespace,
+				Question: fmt.Sprintf("%d pod(s) owned by %s are failing with reason %s: %s.\nThe evidence below is for the most affected pod, %s.",
+					len(group.Pods), group.Owner, group.Reason, strings.Join(names, ", "), pod.Name),
+			}
+			systemPrompt, err := renderPrompt("diagnose-system", promptData)
+			if err != nil {
+				group.Err = err
+				return
+			}
+			data, condenseUsage, err := condense(ctx, client, systemPrompt, evidence.String(), "Diagnose why pod "+pod.Name+" is unhealthy.")
+			if err != nil {
+				group.Err = err
+				return
+			}
+			promptData.Data = data
+			prompt, err := renderPrompt("diagnose-all", promptData)
+			if err != nil {
+				group.Err = err
+				return
+			}
+
+			// Eşzamanlı çıktılar karışmasın diye her grup kendi tamponuna yazar
+			var out bytes.Buffer
+			resp, _, err := investigate(ctx, client, cluster, systemPrompt, prompt, namespace, &out)
+			if err != nil {
+				group.Err = err
+				return
+			}
+			group.Diagnosis = strings.TrimSpace(out.String())
+			group.RootCause = rootCauseLine(group.Diagnosis)
+
+			mu.Lock()
+			usage = addUsage(usage, addUsage(resp.Usage, condenseUsage))
+			model = resp.Model
+			mu.Unlock()
+		}(group)
+	}
+	wg.Wait()
+
+	var firstErr error
+	for i, group := range groups {
+		fmt.Printf("\n🛠️ [%d/%d] %s — %s (%d pod(s), %d restart(s))\n", i+1, len(groups), group.Owner, group.Reason, len(group.Pods), group.Restarts)
+		if group.Err != nil {
+			fmt.Printf("❌ %v\n", group.Err)
+			if firstErr == nil {
+				firstErr = group.Err
+			}
+			continue
+		}
+		fmt.Println(group.Diagnosis)
+	}
+
+	fmt.Println("\n📋 Ranked summary:")
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "RANK\tOWNER\tREASON\tPODS\tRESTARTS\tROOT CAUSE")
+	for i, group := range groups {
+		cause := group.RootCause
+		if group.Err != nil {
+			cause = "diagnosis failed"
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%d\t%d\t%s\n", i+1, group.Owner, group.Reason, len(group.Pods), group.Restarts, cause)
+	}
+	w.Flush()
+
+	printRedactions()
+	printUsage(model, usage)
+	return firstErr
+}
+
+// rootCauseLine, modelin cevabındaki "Root cause:" satırını çıkarır.
+func rootCauseLine(diagnosis string) string {
+	for _, line := range strings.Split(diagnosis, "\n") {
+		line = strings.TrimSpace(strings.Trim(line, "*# "))
+		if i := strings.Index(strings.ToLower(line), "root cause:"); i >= 0 {
+			return strings.TrimSpace(strings.Trim(line[i+len("root cause:"):], "* "))
+		}
+	}
+	if first := strings.SplitN(diagnosis, "\n", 2)[0]; len(first) <= 120 {
+		return first
+	}
+	return "see diagnosis above"
+}
@@ -0,0 +1,181 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	openai "github.com/sashabaranov/go-openai"
+)
+
+// Çıkış kodları. main.go, RootCmd'den dönen hatayı ExitCode ile koda çevirir.
+// Bu değerler CI script'lerinin dayandığı sabit bir arayüzdür; değiştirmeyin.
+const (
+	ExitOK              = 0
+	ExitError           = 1
+	ExitUsage           = 2
+	ExitCluster         = 3
+	ExitAIError         = 4
+	ExitFindings        = 5
+	ExitAIAuth          = 6
+	ExitAIQuota         = 7
+	ExitAIContextLength = 8
+	ExitAINetwork       = 9
+	ExitCancelled       = 130
+)
+
+// UsageError, eksik/hatalı flag, argüman veya girdi dosyası gibi kullanıcı hatalarıdır.
+type UsageError struct {
+	Err error
+}
+
+func (e *UsageError) Error() string { return e.Err.Error() }
+func (e *UsageError) Unwrap() error { return e.Err }
+func (e *UsageError) ExitCode() int { return ExitUsage }
+
+func usageErrorf(format string, args ...interface{}) error {
+	return &UsageError{Err: fmt.Errorf(format, args...)}
+}
+
+// ClusterError, Kubernetes cluster'ına erişirken oluşan hatalardır.
+type ClusterError struct {
+	Err error
+}
+
+func (e *ClusterError) Error() string { return e.Err.Error() }
+func (e *ClusterError) Unwrap() error { return e.Err }
+func (e *ClusterError) ExitCode() int { return ExitCluster }
+
+func clusterErrorf(format string, args ...interface{}) error {
+	return &ClusterError{Err: fmt.Errorf(format, args...)}
+}
+
+// FindingsError, bulgular eşik değerini aştığında döndürülür. Komut başarıyla
+// çalışmıştır; yalnızca CI'ın adımı başarısız sayması için sıfırdan farklı
+// bir kodla çıkılır.
+type FindingsError struct {
+	Count     int
+	Threshold string
+}
+
+func (e *FindingsError) Error() string {
+	return fmt.Sprintf("%d finding(s) at or above severity %q", e.Count, e.Threshold)
+}
+func (e *FindingsError) ExitCode() int { return ExitFindings }
+
+// AIErrorKind, bir AI isteğinin neden başarısız olduğunu sınıflandırır.
+type AIErrorKind string
+
+const (
+	AIErrorAuth          AIErrorKind = "auth"
+	AIErrorQuota         AIErrorKind = "quota"
+	AIErrorContextLength AIErrorKind = "context-length"
+	AIErrorNetwork       AIErrorKind = "network"
+	AIErrorCancelled     AIErrorKind = "cancelled"
+	AIErrorOther         AIErrorKind = "other"
+)
+
+// AIError, sınıflandırılmış bir LLM sağlayıcı hatasıdır.
+type AIError struct {
+	Kind AIErrorKind
+	Err  error
+}
+
+func (e *AIError) Error() string {
+	hint := ""
+	switch e.Kind {
+	case AIErrorAuth:
+		hint = " (check the API key for --provider " + ProviderName + ")"
+	case AIErrorQuota:
+		hint = " (rate limit or quota exceeded; retry later or check your plan)"
+	case AIErrorContextLength:
+		hint = " (input is too large for model " + Model + "; reduce the input or use a model with a larger context)"
+	case AIErrorNetwork:
+		hint = " (could not reach the AI endpoint; check --base-url, network and --timeout)"
+	}
+	return fmt.Sprintf("AI request failed [%s]: %v%s", e.Kind, e.Err, hint)
+}
+
+func (e *AIError) Unwrap() error { return e.Err }
+
+// ExitCode, hatanın sınıfına karşılık gelen çıkış kodunu döndürür.
+func (e *AIError) ExitCode() int {
+	switch e.Kind {
+	case AIErrorAuth:
+		return ExitAIAuth
+	case AIErrorQuota:
+		return ExitAIQuota
+	case AIErrorContextLength:
+		return ExitAIContextLength
+	case AIErrorNetwork:
+		return ExitAINetwork
+	case AIErrorCancelled:
+		return ExitCancelled
+	}
+	return ExitAIError
+}
+
+// ExitCode, RootCmd'den dönen hatayı çıkış koduna çevirir.
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitOK
+	}
+	var coder interface{ ExitCode() int }
+	if errors.As(err, &coder) {
+		return coder.ExitCode()
+	}
+	return ExitError
+}
+
+// classifyAIError, sağlayıcıdan dönen hatayı AIError'a sarar.
+func classifyAIError(err error) error {
+	if err == nil {
+		return nil
+	}
+	var aiErr *AIError
+	if errors.As(err, &aiErr) {
+		return err
+	}
+	return &AIError{Kind: aiErrorKind(err), Err: err}
+}
+
+func aiErrorKind(err error) AIErrorKind {
+	if errors.Is(err, context.Canceled) {
+		return AIErrorCancelled
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return AIErrorNetwork
+	}
+
+	status, code, message := 0, "", err.Error()
+	var apiErr *openai.APIError
+	var reqErr *openai.RequestError
+	if errors.As(err, &apiErr) {
+		status, message = apiErr.HTTPStatusCode, apiErr.Message
+		if c, ok := apiErr.Code.(string); ok {
+			code = c
+		}
+	} else if errors.As(err, &reqErr) {
+		status = reqErr.HTTPStatusCode
+	}
+
+	switch {
+	case status == http.StatusUnauthorized || status == http.StatusForbidden:
+		return AIErrorAuth
+	case status == http.StatusTooManyRequests:
+		return AIErrorQuota
+	case code == "context_length_exceeded" || strings.Contains(message, "maximum context length"):
+		return AIErrorContextLength
+	case status >= 500:
+		return AIErrorNetwork
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return AIErrorNetwork
+	}
+	return AIErrorOther
+}
@@ -0,0 +1,233 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// Bir workload hedeflendiğinde logları toplanacak en fazla pod sayısı.
+	maxEvidencePods = 3
+	// Sahip zincirinde izlenecek en fazla adım (Pod → ReplicaSet → Deployment ...).
+	maxOwnerDepth = 5
+)
+
+// EvidenceOptions, toplanacak log miktarını belirler.
+type EvidenceOptions struct {
+	Since     time.Duration
+	Tail      int64
+	Container string
+}
+
+// EvidenceSection, modele gönderilen etiketli bir kanıt bölümüdür.
+type EvidenceSection struct {
+	Title string
+	Body  string
+}
+
+// Evidence, bir hedef için tek seferde toplanan kanıt paketidir.
+type Evidence struct {
+	Sections []EvidenceSection
+}
+
+func (e *Evidence) add(title, body string) {
+	if strings.TrimSpace(body) == "" {
+		body = "<none>"
+	}
+	e.Sections = append(e.Sections, EvidenceSection{Title: title, Body: strings.TrimRight(body, "\n")})
+}
+
+// String, bölümleri modelin kolayca ayırt edebileceği başlıklarla birleştirir.
+func (e *Evidence) String() string {
+	var b strings.Builder
+	for _, section := range e.Sections {
+		fmt.Fprintf(&b, "===== %s =====\n%s\n\n", section.Title, section.Body)
+	}
+	return b.String()
+}
+
+// CollectEvidence, hedef kaynak için spec, status, zamana göre sıralı event'ler,
+// sahip zinciri (Pod → ReplicaSet → Deployment) ve her container'ın güncel ve
+// önceki loglarını tek seferde toplar. Hedef bir workload ise logları
+// selector'ına uyan ilk birkaç pod'dan alınır.
+func (c *Cluster) CollectEvidence(ctx context.Context, resource, name, namespace string, opts EvidenceOptions) (*Evidence, error) {
+	obj, err := c.Get(ctx, resource, name, namespace)
+	if err != nil {
+		return nil, err
+	}
+	namespace = obj.GetNamespace()
+	ref := objectRef(obj)
+
+	evidence := &Evidence{}
+	c.addObjectSections(ctx, evidence, obj)
+
+	// Sahip zinciri: Pod → ReplicaSet → Deployment gibi
+	chain := []string{ref}
+	owner := obj
+	for depth := 0; depth < maxOwnerDepth; depth++ {
+		next, err := c.controllerOf(ctx, owner)
+		if err != nil || next == nil {
+			break
+		}
+		chain = append(chain, objectRef(next))
+		c.addObjectSections(ctx, evidence, next)
+		owner = next
+	}
+	evidence.add("OWNER CHAIN", strings.Join(chain, " → "))
+
+	// Loglar
+	pods, err := c.podsFor(ctx, obj)
+	if err != nil {
+		evidence.add("LOGS", fmt.Sprintf("error: %v", err))
+		return evidence, nil
+	}
+	for _, pod := range pods {
+		c.addLogSections(ctx, evidence, pod, opts)
+	}
+	return evidence, nil
+}
+
+// addObjectSections, bir nesnenin spec, status ve event bölümlerini ekler.
+func (c *Cluster) addObjectSections(ctx context.Context, evidence *Evidence, obj *unstructured.Unstructured) {
+	ref := objectRef(obj)
+
+	spec := obj.DeepCopy()
+	unstructured.RemoveNestedField(spec.Object, "status")
+	if out, err := yaml.Marshal(spec.Object); err == nil {
+		evidence.add("SPEC: "+ref, string(out))
+	}
+
+	if status, ok, _ := unstructured.NestedMap(obj.Object, "status"); ok {
+		if out, err := yaml.Marshal(status); err == nil {
+			evidence.add("STATUS: "+ref, string(out))
+		}
+	}
+
+	if obj.GetNamespace() != "" {
+		events, err := c.Events(ctx, obj.GetNamespace(), obj.GetName())
+		if err != nil {
+			events = fmt.Sprintf("error: %v", err)
+		}
+		evidence.add("EVENTS: "+ref+" (oldest first)", events)
+	}
+}
+
+// controllerOf, nesnenin controller sahibini döndürür; yoksa nil.
+func (c *Cluster) controllerOf(ctx context.Context, obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	owner := metav1.GetControllerOf(obj)
+	if owner == nil {
+		return nil, nil
+	}
+	gv, err := schema.ParseGroupVersion(owner.APIVersion)
+	if err != nil {
+		return nil, err
+	}
+	mapping, err := c.Mapper.RESTMapping(schema.GroupKind{Group: gv.Group, Kind: owner.Kind}, gv.Version)
+	if err != nil {
+		return nil, err
+	}
+	parent, err := c.resourceClient(mapping, obj.GetNamespace()).Get(ctx, owner.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	if !Raw {
+		normalizeObject(parent.Object)
+	}
+	redactor().Object(parent.Object, objectRef(parent))
+	return parent, nil
+}
+
+// podsFor, hedef bir pod ise kendisini, bir workload ise selector'ına uyan pod'ları döndürür.
+func (c *Cluster) podsFor(ctx context.Context, obj *unstructured.Unstructured) ([]*corev1.Pod, error) {
+	if obj.GetKind() == "Pod" {
+		var pod corev1.Pod
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, &pod); err != nil {
+			return nil, err
+		}
+		return []*corev1.Pod{&pod}, nil
+	}
+
+	raw, ok, _ := unstructured.NestedMap(obj.Object, "spec", "selector")
+	if !ok {
+		return nil, nil
+	}
+	var labelSelector metav1.LabelSelector
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(raw, &labelSelector); err != nil {
+		return nil, err
+	}
+	selector, err := metav1.LabelSelectorAsSelector(&labelSelector)
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := c.Clientset.CoreV1().Pods(obj.GetNamespace()).List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return nil, clusterErrorf("failed to list pods for %s: %w", objectRef(obj), err)
+	}
+	var pods []*corev1.Pod
+	for i := range list.Items {
+		if len(pods) == maxEvidencePods {
+			break
+		}
+		pods = append(pods, &list.Items[i])
+	}
+	return pods, nil
+}
+
+// addLogSections, pod'daki her container için güncel logları ve container
+// yeniden başlamışsa önceki instance'ın loglarını ekler.
+func (c *Cluster) addLogSections(ctx context.Context, evidence *Evidence, pod *corev1.Pod, opts EvidenceOptions) {
+	restarts := map[string]int32{}
+	for _, cs := range append(pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses...) {
+		restarts[cs.Name] = cs.RestartCount
+	}
+
+	var containers []string
+	for _, container := range append(pod.Spec.InitContainers, pod.Spec.Containers...) {
+		if opts.Container == "" || opts.Container == container.Name {
+			containers = append(containers, container.Name)
+		}
+	}
+
+	for _, container := range containers {
+		logOpts := &corev1.PodLogOptions{Container: container}
+		if opts.Tail > 0 {
+			logOpts.TailLines = &opts.Tail
+		}
+		if opts.Since > 0 {
+			seconds := int64(opts.Since.Seconds())
+			logOpts.SinceSeconds = &seconds
+		}
+
+		title := fmt.Sprintf("LOGS: pod/%s container=%s", pod.Name, container)
+		logs, err := c.podLogs(ctx, pod.Name, pod.Namespace, logOpts)
+		if err != nil {
+			logs = fmt.Sprintf("error: %v", err)
+		}
+		evidence.add(title+" (current)", logs)
+
+		if restarts[container] > 0 {
+			previous := *logOpts
+			previous.Previous = true
+			logs, err := c.podLogs(ctx, pod.Name, pod.Namespace, &previous)
+			if err != nil {
+				logs = fmt.Sprintf("error: %v", err)
+			}
+			evidence.add(fmt.Sprintf("%s (previous, restarts=%d)", title, restarts[container]), logs)
+		}
+	}
+}
+
+// objectRef, "deployment/web" biçiminde kısa bir referans döndürür.
+func objectRef(obj *unstructured.Unstructured) string {
+	return strings.ToLower(obj.GetKind()) + "/" + obj.GetName()
+}
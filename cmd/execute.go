@@ -3,34 +3,33 @@ package cmd
 import (
 	"fmt"
 	"os"
-	"os/exec"
 
 	"github.com/spf13/cobra"
 )
 
-var execFile string
+var (
+	execFile           string
+	execForceConflicts bool
+)
 
 var ExecuteCmd = &cobra.Command{
 	Use:   "execute --file <yaml-file>",
 	Short: "Apply a Kubernetes manifest file to the cluster",
-	Long:  "Apply an existing Kubernetes YAML manifest to the cluster. The manifest content is displayed before applying.",
-	Run: func(cmd *cobra.Command, args []string) {
+	Long:  "Apply an existing Kubernetes YAML manifest to the cluster using server-side apply. The manifest content is displayed before applying. Fields owned by another field manager (e.g. Helm, ArgoCD or an HPA) are not changed and the conflict is reported, unless --force-conflicts is given. Namespaced resources without a namespace go to -n or the kubeconfig context namespace.",
+	RunE: func(cmd *cobra.Command, args []string) error {
 		if execFile == "" {
-			fmt.Println("❌ Please provide a file using --file or -f flag.")
-			return
+			return usageErrorf("please provide a file using --file or -f flag")
 		}
 
 		if _, err := os.Stat(execFile); os.IsNotExist(err) {
-			fmt.Printf("❌ File '%s' does not exist.\n", execFile)
-			return
+			return usageErrorf("file '%s' does not exist", execFile)
 		}
 
 		SaveToHistory("execute", fmt.Sprintf("file=%s", execFile))
 
 		content, err := os.ReadFile(execFile)
 		if err != nil {
-			fmt.Printf("❌ Failed to read file '%s': %v\n", execFile, err)
-			return
+			return usageErrorf("failed to read file '%s': %w", execFile, err)
 		}
 
 		fmt.Println("\n📄 YAML Content to Apply:")
@@ -40,21 +39,25 @@ var ExecuteCmd = &cobra.Command{
 
 		fmt.Println("🚀 Applying manifest to the cluster...")
 
-		applyCmd := exec.Command("kubectl", "apply", "-f", execFile)
-		applyCmd.Stdout = os.Stdout
-		applyCmd.Stderr = os.Stderr
-		applyCmd.Stdin = os.Stdin
-
-		if err := applyCmd.Run(); err != nil {
-			fmt.Printf("❌ Failed to apply manifest: %v\n", err)
-			return
+		cluster, err := NewCluster()
+		if err != nil {
+			return err
+		}
+		applied, err := cluster.Apply(cmd.Context(), content, execForceConflicts)
+		for _, resource := range applied {
+			fmt.Printf("  %s applied\n", resource)
+		}
+		if err != nil {
+			return err
 		}
 
 		fmt.Println("✅ Resource applied successfully!")
 		fmt.Printf("📄 YAML remains at: %s\n", execFile)
+		return nil
 	},
 }
 
 func init() {
 	ExecuteCmd.Flags().StringVarP(&execFile, "file", "f", "", "Path to the YAML manifest file to apply")
+	ExecuteCmd.Flags().BoolVar(&execForceConflicts, "force-conflicts", false, "Take over fields that another field manager (e.g. Helm, ArgoCD or an HPA) owns")
 }
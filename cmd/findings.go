@@ -0,0 +1,214 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	openai "github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/jsonschema"
+)
+
+// Bulgu önem dereceleri, düşükten yükseğe.
+var severities = []string{"info", "low", "medium", "high", "critical"}
+
+// severityRank, önem derecesinin sırasıdır; bilinmeyen değerler -1 döner.
+func severityRank(severity string) int {
+	for i, s := range severities {
+		if s == strings.ToLower(severity) {
+			return i
+		}
+	}
+	return -1
+}
+
+// Finding, audit'in tek bir bulgusudur. JSON alan adları -o json çıktısının
+// sabit şemasıdır; CI script'leri buna dayanır.
+type Finding struct {
+	RuleID      string `json:"ruleId"`
+	Severity    string `json:"severity"`
+	Resource    string `json:"resource"`
+	FieldPath   string `json:"fieldPath"`
+	Namespace   string `json:"namespace,omitempty"` // bilinmiyorsa veya cluster kapsamlıysa boş
+	Description string `json:"description"`
+	Remediation string `json:"remediation"`
+	// Source, bulgunun dahili bir kuraldan mı (rule), kurum politikasından mı (policy)
+	// yoksa AI'dan mı (ai) geldiğidir.
+	Source string `json:"source"`
+	// Explanation, AI'ın kural bulgusuna eklediği bağlamdır.
+	Explanation string `json:"explanation,omitempty"`
+	// File ve Line yalnızca -f ile verilen dosyalar için doldurulur.
+	File string `json:"file,omitempty"`
+	Line int    `json:"line,omitempty"`
+}
+
+// AuditReport, -o json ile yazdırılan audit sonucudur.
+type AuditReport struct {
+	Findings []Finding `json:"findings"`
+}
+
+const reportFindingsTool = "report_findings"
+
+// findingsToolDefinition, modelin bulguları bildirmek için çağırmak zorunda olduğu fonksiyondur.
+func findingsToolDefinition() openai.Tool {
+	return openai.Tool{
+		Type: openai.ToolTypeFunction,
+		Function: &openai.FunctionDefinition{
+			Name:        reportFindingsTool,
+			Description: "Report all security findings of the audit. Call this exactly once with every finding; use an empty list when there are none.",
+			Parameters: jsonschema.Definition{
+				Type: jsonschema.Object,
+				Properties: map[string]jsonschema.Definition{
+					"findings": {
+						Type: jsonschema.Array,
+						Items: &jsonschema.Definition{
+							Type: jsonschema.Object,
+							Properties: map[string]jsonschema.Definition{
+								"ruleId":      {Type: jsonschema.String, Description: "Stable kebab-case identifier of the check, e.g. privileged-container, missing-resource-limits, image-latest-tag"},
+								"severity":    {Type: jsonschema.String, Enum: severities, Description: "Severity of the finding"},
+								"resource":    {Type: jsonschema.String, Description: "Affected resource as kind/name in lower case, e.g. deployment/web"},
+								"fieldPath":   {Type: jsonschema.String, Description: "Path of the offending field inside the resource, e.g. spec.template.spec.containers[0].securityContext.privileged; empty if the field is missing at resource level"},
+								"description": {Type: jsonschema.String, Description: "What is wrong and why it is a risk, in one or two sentences"},
+								"remediation": {Type: jsonschema.String, Description: "Concrete change that fixes the finding"},
+							},
+							Required: []string{"ruleId", "severity", "resource", "fieldPath", "description", "remediation"},
+						},
+					},
+				},
+				Required: []string{"findings"},
+			},
+		},
+	}
+}
+
+// findingsInstruction, yapılandırılmış audit'te sistem mesajına eklenir.
+const findingsInstruction = `
+
+Report your result only by calling the report_findings function, with one entry per problem and per affected resource.
+Use the same ruleId for the same kind of problem. Do not write any other text.`
+
+// requestFindings, modeli report_findings fonksiyonunu çağırmaya zorlar ve bulguları döndürür.
+func requestFindings(ctx context.Context, client Provider, systemPrompt, userPrompt string) ([]Finding, openai.ChatCompletionResponse, error) {
+	resp, err := complete(ctx, client, openai.ChatCompletionRequest{
+		Model: Model,
+		Messages: []openai.ChatCompletionMessage{
+			{Role: openai.ChatMessageRoleSystem, Content: systemPrompt + findingsInstruction},
+			{Role: openai.ChatMessageRoleUser, Content: userPrompt},
+		},
+		MaxTokens: MaxTokens,
+		Tools:     []openai.Tool{findingsToolDefinition()},
+		ToolChoice: openai.ToolChoice{
+			Type:     openai.ToolTypeFunction,
+			Function: openai.ToolFunction{Name: reportFindingsTool},
+		},
+	}, io.Discard)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	msg := resp.Choices[0].Message
+	raw := msg.Content
+	for _, call := range msg.ToolCalls {
+		if call.Function.Name == reportFindingsTool {
+			raw = call.Function.Arguments
+			break
+		}
+	}
+	// Fonksiyon çağırmayı desteklemeyen modeller JSON'u metin olarak döndürebilir
+	if start, end := strings.Index(raw, "{"), strings.LastIndex(raw, "}"); start >= 0 && end > start {
+		raw = raw[start : end+1]
+	}
+
+	var report AuditReport
+	if err := json.Unmarshal([]byte(raw), &report); err != nil {
+		return nil, resp, &AIError{Kind: AIErrorOther, Err: fmt.Errorf("model did not return structured findings: %w", err)}
+	}
+	for i := range report.Findings {
+		f := &report.Findings[i]
+		f.Severity = strings.ToLower(f.Severity)
+		f.Source = SourceAI
+		if severityRank(f.Severity) < 0 {
+			f.Severity = "medium"
+		}
+	}
+	sortFindings(report.Findings)
+	return report.Findings, resp, nil
+}
+
+// sortFindings, bulguları önem derecesine (yüksekten düşüğe), kaynağa ve kurala göre sıralar.
+func sortFindings(findings []Finding) {
+	sort.SliceStable(findings, func(i, j int) bool {
+		a, b := findings[i], findings[j]
+		if ra, rb := severityRank(a.Severity), severityRank(b.Severity); ra != rb {
+			return ra > rb
+		}
+		if a.Resource != b.Resource {
+			return a.Resource < b.Resource
+		}
+		return a.RuleID < b.RuleID
+	})
+}
+
+// writeFindingsTable, bulguları okunabilir bir tablo ve düzeltme listesi olarak yazar.
+func writeFindingsTable(out io.Writer, findings []Finding) {
+	if len(findings) == 0 {
+		fmt.Fprintln(out, "✅ No findings.")
+		return
+	}
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "#\tSEVERITY\tSOURCE\tRULE\tRESOURCE\tFIELD\tDESCRIPTION")
+	for i, f := range findings {
+		field := f.FieldPath
+		if field == "" {
+			field = "-"
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\t%s\n", i+1, strings.ToUpper(f.Severity), f.Source, f.RuleID, f.Resource, field, f.Description)
+	}
+	w.Flush()
+
+	fmt.Fprintln(out, "\n🔧 Remediation:")
+	for i, f := range findings {
+		fmt.Fprintf(out, "  [%d] %s\n", i+1, f.Remediation)
+		if f.Explanation != "" {
+			fmt.Fprintf(out, "      💡 %s\n", f.Explanation)
+		}
+	}
+}
+
+// writeSeveritySummary, önem derecesi başına bulgu sayısını yüksekten düşüğe yazar.
+func writeSeveritySummary(out io.Writer, findings []Finding) {
+	counts := make(map[string]int)
+	for _, f := range findings {
+		counts[f.Severity]++
+	}
+	var parts []string
+	for i := len(severities) - 1; i >= 0; i-- {
+		parts = append(parts, fmt.Sprintf("%d %s", counts[severities[i]], severities[i]))
+	}
+	fmt.Fprintf(out, "\n📊 Summary: %d finding(s): %s\n", len(findings), strings.Join(parts, ", "))
+}
+
+// countAtOrAbove, eşik değerinde veya üstündeki bulguları sayar.
+func countAtOrAbove(findings []Finding, threshold string) int {
+	count := 0
+	for _, f := range findings {
+		if severityRank(f.Severity) >= severityRank(threshold) {
+			count++
+		}
+	}
+	return count
+}
+
+// writeFindingsJSON, bulguları sabit şemalı JSON olarak yazar.
+func writeFindingsJSON(out io.Writer, findings []Finding) error {
+	if findings == nil {
+		findings = []Finding{}
+	}
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(AuditReport{Findings: findings})
+}
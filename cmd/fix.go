@@ -0,0 +1,436 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+	yaml "go.yaml.in/yaml/v3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// placeholderComment, --fix'in eklediği varsayılan kaynak değerlerinin yorumudur.
+const placeholderComment = "kube-ai: placeholder, size for the workload"
+
+// runAsNonRootTodo, runAsNonRoot güvenle eklenemediğinde pod securityContext'inin üstüne yazılan yorumdur.
+const runAsNonRootTodo = "kube-ai: TODO set runAsNonRoot: true and a non-zero runAsUser once the image runs as non-root"
+
+// Varsayılan kaynak değerleri; container'da ne request ne limit varsa kullanılır.
+// Yalnızca biri varsa eksik olan ona eşitlenir.
+var (
+	placeholderRequests = map[string]string{"cpu": "100m", "memory": "128Mi"}
+	placeholderLimits   = map[string]string{"cpu": "500m", "memory": "256Mi"}
+)
+
+// fixChange, --fix'in manifest'te yaptığı tek bir değişikliktir.
+type fixChange struct {
+	Resource  string
+	FieldPath string
+}
+
+// manifestFixer, pod tanımlarına güvenli varsayılanları ekler. Yalnızca eksik
+// alanlar eklenir; açıkça verilmiş değerler değiştirilmez.
+type manifestFixer struct {
+	digests map[string]string // normalize edilmiş imaj → sha256 digest'i
+	changes []fixChange
+}
+
+// fixManifest, YAML metnindeki pod tanımlarına güvenli düzeltmeleri uygular ve
+// düzeltilmiş metni döndürür. Yorumlar, sıralama ve stil korunur.
+func fixManifest(source string, digests map[string]string) (string, []fixChange, error) {
+	fixer := &manifestFixer{digests: digests}
+	dec := yaml.NewDecoder(strings.NewReader(source))
+	var docs []*yaml.Node
+	for {
+		var doc yaml.Node
+		if err := dec.Decode(&doc); err == io.EOF {
+			break
+		} else if err != nil {
+			return "", nil, usageErrorf("failed to parse manifest: %w", err)
+		}
+		docs = append(docs, &doc)
+		if len(doc.Content) > 0 {
+			fixer.fixObject(doc.Content[0])
+		}
+	}
+	if len(fixer.changes) == 0 {
+		return source, nil, nil
+	}
+
+	var out bytes.Buffer
+	enc := yaml.NewEncoder(&out)
+	enc.SetIndent(2)
+	if compactSequences(source) {
+		enc.CompactSeqIndent()
+	}
+	for _, doc := range docs {
+		if err := enc.Encode(doc); err != nil {
+			return "", nil, err
+		}
+	}
+	if err := enc.Close(); err != nil {
+		return "", nil, err
+	}
+	return out.String(), fixer.changes, nil
+}
+
+// seqItemPattern, bir anahtarın altındaki ilk liste elemanını (aradaki
+// yorum satırlarını atlayarak) yakalar.
+var seqItemPattern = regexp.MustCompile(`(?m)^( *)[^ #\n-][^\n]*:\n(?: *#[^\n]*\n)*( *)- `)
+
+// compactSequences, kaynağın liste elemanlarını anahtarla aynı girintide
+// (kubectl tarzı) yazıp yazmadığını söyler; çıktı aynı stili kullanır.
+func compactSequences(source string) bool {
+	m := seqItemPattern.FindStringSubmatch(source)
+	return m == nil || len(m[1]) == len(m[2])
+}
+
+// fixObject, nesnedeki (List ise elemanlarındaki) pod tanımlarını düzeltir.
+func (f *manifestFixer) fixObject(obj *yaml.Node) {
+	kind := mappingScalar(obj, "kind")
+	if items := mappingValue(obj, "items"); items != nil && items.Kind == yaml.SequenceNode {
+		for _, item := range items.Content {
+			f.fixObject(item)
+		}
+		return
+	}
+	path, ok := podSpecPaths[kind]
+	if !ok {
+		return
+	}
+	spec := obj
+	for _, key := range strings.Split(path, ".") {
+		if spec = mappingValue(spec, key); spec == nil || spec.Kind != yaml.MappingNode {
+			return
+		}
+	}
+	resource := strings.ToLower(kind) + "/" + mappingScalar(mappingValue(obj, "metadata"), "name")
+	f.fixPodSpec(resource, path, spec)
+}
+
+// fixPodSpec, pod ve container securityContext varsayılanlarını, kaynak
+// yer tutucularını ve bilinen imaj digest'lerini ekler.
+func (f *manifestFixer) fixPodSpec(resource, path string, spec *yaml.Node) {
+	containers := podContainers(spec)
+
+	podContext := ensureMapping(spec, "securityContext")
+	// runAsNonRoot, root olarak çalışan imajları (nginx, redis gibi çoğu genel
+	// imaj) başlatmaz; yalnızca her container'ın UID'i sıfırdan farklıysa eklenir.
+	// Açıkça root (UID 0) istenmediyse yerine bir TODO yorumu bırakılır.
+	switch users := containerUsers(podContext, containers); {
+	case mappingKey(podContext, "runAsNonRoot") != nil || slices.Contains(users, "0"):
+	case len(users) > 0 && !slices.ContainsFunc(users, func(u string) bool { return !isNonRootUID(u) }):
+		f.setDefault(resource, path+".securityContext", podContext, "runAsNonRoot", boolNode(true))
+	default:
+		f.addComment(resource, path+".securityContext.runAsNonRoot", spec, "securityContext", runAsNonRootTodo)
+	}
+	f.setDefault(resource, path+".securityContext", podContext, "seccompProfile", mappingNode("type", "RuntimeDefault"))
+	removeIfEmpty(spec, "securityContext")
+
+	for _, c := range containers {
+		f.fixContainer(resource, path+"."+c.path, c.node)
+	}
+}
+
+// containerUsers, her container'ın etkin runAsUser değerini döndürür: kendi
+// securityContext'indeki veya pod'unki; ikisi de yoksa boş.
+func containerUsers(podContext *yaml.Node, containers []containerNode) []string {
+	podUser := mappingScalar(podContext, "runAsUser")
+	users := make([]string, len(containers))
+	for i, c := range containers {
+		users[i] = mappingScalar(mappingValue(c.node, "securityContext"), "runAsUser")
+		if users[i] == "" {
+			users[i] = podUser
+		}
+	}
+	return users
+}
+
+// isNonRootUID, değerin sıfırdan büyük bir UID olup olmadığını söyler.
+func isNonRootUID(user string) bool {
+	uid, err := strconv.ParseInt(user, 10, 64)
+	return err == nil && uid > 0
+}
+
+// containerNode, pod spec'indeki bir container ve yoludur.
+type containerNode struct {
+	node *yaml.Node
+	path string
+}
+
+// podContainers, init container'lar dahil tüm container'ları döndürür.
+func podContainers(spec *yaml.Node) []containerNode {
+	var containers []containerNode
+	for _, key := range []string{"initContainers", "containers"} {
+		list := mappingValue(spec, key)
+		if list == nil || list.Kind != yaml.SequenceNode {
+			continue
+		}
+		for i, c := range list.Content {
+			if c.Kind == yaml.MappingNode {
+				containers = append(containers, containerNode{c, fmt.Sprintf("%s[%d]", key, i)})
+			}
+		}
+	}
+	return containers
+}
+
+// fixContainer, container'a securityContext varsayılanlarını, kaynak yer
+// tutucularını ve bilinen imaj digest'ini ekler.
+func (f *manifestFixer) fixContainer(resource, path string, container *yaml.Node) {
+	ctx := ensureMapping(container, "securityContext")
+	privileged := mappingScalar(ctx, "privileged") == "true"
+	capabilities := mappingValue(ctx, "capabilities")
+	sysAdmin := false
+	if add := mappingValue(capabilities, "add"); add != nil {
+		for _, c := range add.Content {
+			sysAdmin = sysAdmin || strings.TrimPrefix(c.Value, "CAP_") == "SYS_ADMIN"
+		}
+	}
+	// API, privileged veya CAP_SYS_ADMIN ile allowPrivilegeEscalation: false'u reddeder
+	if !privileged && !sysAdmin {
+		f.setDefault(resource, path+".securityContext", ctx, "allowPrivilegeEscalation", boolNode(false))
+		if mappingValue(capabilities, "drop") == nil {
+			caps := ensureMapping(ctx, "capabilities")
+			f.setDefault(resource, path+".securityContext.capabilities", caps, "drop", &yaml.Node{
+				Kind: yaml.SequenceNode, Tag: "!!seq", Style: yaml.FlowStyle,
+				Content: []*yaml.Node{{Kind: yaml.ScalarNode, Tag: "!!str", Value: "ALL"}},
+			})
+		}
+	}
+	f.setDefault(resource, path+".securityContext", ctx, "readOnlyRootFilesystem", boolNode(true))
+	removeIfEmpty(container, "securityContext")
+
+	resources := ensureMapping(container, "resources")
+	requests := ensureMapping(resources, "requests")
+	limits := ensureMapping(resources, "limits")
+	for _, name := range []string{"cpu", "memory"} {
+		request, limit := mappingValue(requests, name), mappingValue(limits, name)
+		switch {
+		case request == nil && limit == nil:
+			f.setDefault(resource, path+".resources.requests", requests, name, placeholderNode(scalarNode(placeholderRequests[name])))
+			f.setDefault(resource, path+".resources.limits", limits, name, placeholderNode(scalarNode(placeholderLimits[name])))
+		case request == nil:
+			// Kubernetes de limit'i request olarak kullanır
+			f.setDefault(resource, path+".resources.requests", requests, name, placeholderNode(limit))
+		case limit == nil:
+			f.setDefault(resource, path+".resources.limits", limits, name, placeholderNode(request))
+		}
+	}
+	removeIfEmpty(resources, "requests")
+	removeIfEmpty(resources, "limits")
+	removeIfEmpty(container, "resources")
+
+	if image := mappingValue(container, "image"); image != nil && !strings.Contains(image.Value, "@") {
+		if digest, ok := f.digests[normalizeImage(image.Value)]; ok {
+			image.Value += "@" + digest
+			f.changes = append(f.changes, fixChange{resource, path + ".image"})
+		}
+	}
+}
+
+// setDefault, anahtar mapping'de yoksa değeri ekler ve değişikliği kaydeder.
+func (f *manifestFixer) setDefault(resource, path string, mapping *yaml.Node, key string, value *yaml.Node) {
+	if mappingKey(mapping, key) != nil {
+		return
+	}
+	mapping.Content = append(mapping.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, value)
+	f.changes = append(f.changes, fixChange{resource, path + "." + key})
+}
+
+// addComment, anahtarın üstüne (henüz yoksa) yorum ekler ve değişikliği kaydeder.
+func (f *manifestFixer) addComment(resource, path string, mapping *yaml.Node, key, comment string) {
+	node := mappingKey(mapping, key)
+	if node == nil || strings.Contains(node.HeadComment, comment) {
+		return
+	}
+	if node.HeadComment != "" {
+		node.HeadComment += "\n"
+	}
+	node.HeadComment += "# " + comment
+	f.changes = append(f.changes, fixChange{resource, path})
+}
+
+// ensureMapping, anahtarın mapping değerini döndürür; yoksa (veya null ise)
+// boş bir mapping ekler. Boş kalırsa removeIfEmpty ile geri alınır.
+func ensureMapping(parent *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(parent.Content); i += 2 {
+		if parent.Content[i].Value != key {
+			continue
+		}
+		value := parent.Content[i+1]
+		if value.Kind == yaml.ScalarNode && value.Tag == "!!null" {
+			value.Kind, value.Tag, value.Value = yaml.MappingNode, "!!map", ""
+		}
+		return value
+	}
+	value := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	parent.Content = append(parent.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, value)
+	return value
+}
+
+// removeIfEmpty, ensureMapping'in eklediği ve boş kalan anahtarı kaldırır.
+func removeIfEmpty(parent *yaml.Node, key string) {
+	for i := 0; i+1 < len(parent.Content); i += 2 {
+		value := parent.Content[i+1]
+		if parent.Content[i].Value == key && value.Kind == yaml.MappingNode && len(value.Content) == 0 && value.Line == 0 {
+			parent.Content = append(parent.Content[:i], parent.Content[i+2:]...)
+			return
+		}
+	}
+}
+
+func scalarNode(value string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+}
+
+// placeholderNode, değerin gözden geçirilmesi gerektiğini yorumla işaretleyen bir kopyasıdır.
+func placeholderNode(value *yaml.Node) *yaml.Node {
+	return &yaml.Node{Kind: value.Kind, Tag: value.Tag, Style: value.Style, Value: value.Value, LineComment: placeholderComment}
+}
+
+func boolNode(value bool) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: fmt.Sprint(value)}
+}
+
+func mappingNode(key, value string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map", Content: []*yaml.Node{scalarNode(key), scalarNode(value)}}
+}
+
+// normalizeImage, imaj adını karşılaştırma için sadeleştirir: Docker Hub
+// önekleri atılır, etiketi olmayan imaja :latest eklenir.
+func normalizeImage(image string) string {
+	image, _, _ = strings.Cut(image, "@")
+	for _, prefix := range []string{"docker.io/library/", "index.docker.io/library/", "docker.io/", "index.docker.io/"} {
+		image = strings.TrimPrefix(image, prefix)
+	}
+	if !strings.Contains(image[strings.LastIndex(image, "/")+1:], ":") {
+		image += ":latest"
+	}
+	return image
+}
+
+// ImageDigests, namespace'lerdeki çalışan pod'ların imajlarının digest'lerini
+// döndürür. Aynı imaj farklı digest'lerle çalışıyorsa hangisinin doğru olduğu
+// bilinemeyeceği için imaj atlanır.
+func (c *Cluster) ImageDigests(ctx context.Context, namespaces []string) (map[string]string, error) {
+	found := make(map[string]map[string]bool)
+	add := func(image, imageID string) {
+		_, digest, ok := strings.Cut(imageID, "@")
+		if !ok || !strings.HasPrefix(digest, "sha256:") {
+			return
+		}
+		key := normalizeImage(image)
+		if found[key] == nil {
+			found[key] = make(map[string]bool)
+		}
+		found[key][digest] = true
+	}
+	for _, namespace := range namespaces {
+		pods, err := c.Clientset.CoreV1().Pods(c.ResolveNamespace(namespace)).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, clusterErrorf("failed to list pods: %w", err)
+		}
+		for _, pod := range pods.Items {
+			images := make(map[string]string)
+			for _, container := range append(pod.Spec.InitContainers, pod.Spec.Containers...) {
+				images[container.Name] = container.Image
+			}
+			for _, status := range append(pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses...) {
+				add(status.Image, status.ImageID)
+				add(images[status.Name], status.ImageID)
+			}
+		}
+	}
+
+	digests := make(map[string]string)
+	for image, set := range found {
+		if len(set) == 1 {
+			for digest := range set {
+				digests[image] = digest
+			}
+		}
+	}
+	return digests, nil
+}
+
+// manifestNamespaces, manifest'teki nesnelerin namespace'leridir; namespace'i
+// verilmemiş nesneler için boş ad (kubeconfig'deki namespace) kullanılır.
+func manifestNamespaces(objects []map[string]interface{}) []string {
+	seen := make(map[string]bool)
+	var namespaces []string
+	for _, obj := range objects {
+		namespace, _ := lookupPath(obj, "metadata.namespace").(string)
+		if !seen[namespace] {
+			seen[namespace] = true
+			namespaces = append(namespaces, namespace)
+		}
+	}
+	return namespaces
+}
+
+// fixOutputPath, düzeltilmiş kopyanın yoludur: --fix-output veya
+// deploy.yaml için deploy.fixed.yaml.
+func fixOutputPath() string {
+	if auditFixOutput != "" {
+		return auditFixOutput
+	}
+	ext := filepath.Ext(auditInputFile)
+	return strings.TrimSuffix(auditInputFile, ext) + ".fixed" + ext
+}
+
+// runFix, -f ile verilen manifest'in düzeltilmiş kopyasını yazar ve
+// değişiklikleri birleşik diff olarak gösterir.
+func runFix(ctx context.Context, source string) error {
+	var digests map[string]string
+	if auditPinDigests {
+		cluster, err := NewCluster()
+		if err != nil {
+			return err
+		}
+		objects, _ := decodeManifest(source) // hatalı dokümanları audit zaten bildirdi
+		if digests, err = cluster.ImageDigests(ctx, manifestNamespaces(objects)); err != nil {
+			return err
+		}
+	}
+
+	fixed, changes, err := fixManifest(source, digests)
+	if err != nil {
+		return err
+	}
+	if len(changes) == 0 {
+		fmt.Fprintln(statusOut, "\n🩹 No safe fixes to apply.")
+		return nil
+	}
+	output := fixOutputPath()
+	if err := os.WriteFile(output, []byte(fixed), 0644); err != nil {
+		return fmt.Errorf("failed to write fixed manifest %s: %w", output, err)
+	}
+
+	resources := make(map[string]bool)
+	for _, c := range changes {
+		resources[c.Resource] = true
+	}
+	fmt.Fprintf(statusOut, "\n🩹 Applied %d fix(es) to %d resource(s) and wrote %s:\n", len(changes), len(resources), output)
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(source),
+		B:        difflib.SplitLines(fixed),
+		FromFile: auditInputFile,
+		ToFile:   output,
+		Context:  3,
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(statusOut, "\n%s", diff)
+	fmt.Fprintf(statusOut, "\n➡️ Review the placeholders, then apply with: kube-ai execute --file %s\n", output)
+	return nil
+}
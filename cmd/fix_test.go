@@ -0,0 +1,359 @@
+package cmd
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestFixManifest(t *testing.T) {
+	tests := []struct {
+		name     string
+		manifest string
+		digests  map[string]string
+		want     string
+		changes  []string // boşsa kontrol edilmez
+	}{
+		{
+			name: "non-root user gets runAsNonRoot",
+			manifest: `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  template:
+    spec:
+      securityContext:
+        runAsUser: 10001
+      containers:
+      - name: web
+        image: nginx:1.27
+        resources:
+          requests: {cpu: 100m, memory: 64Mi}
+          limits: {cpu: 200m, memory: 128Mi}
+`,
+			want: `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  template:
+    spec:
+      securityContext:
+        runAsUser: 10001
+        runAsNonRoot: true
+        seccompProfile:
+          type: RuntimeDefault
+      containers:
+      - name: web
+        image: nginx:1.27
+        resources:
+          requests: {cpu: 100m, memory: 64Mi}
+          limits: {cpu: 200m, memory: 128Mi}
+        securityContext:
+          allowPrivilegeEscalation: false
+          capabilities:
+            drop: [ALL]
+          readOnlyRootFilesystem: true
+`,
+		},
+		{
+			name: "root user is left alone",
+			manifest: `apiVersion: v1
+kind: Pod
+metadata:
+  name: legacy
+spec:
+  containers:
+  - name: app
+    image: legacy:3 # runs as root
+    securityContext:
+      runAsUser: 0
+    resources:
+      limits: {cpu: 250m, memory: 64Mi}
+`,
+			want: `apiVersion: v1
+kind: Pod
+metadata:
+  name: legacy
+spec:
+  containers:
+  - name: app
+    image: legacy:3 # runs as root
+    securityContext:
+      runAsUser: 0
+      allowPrivilegeEscalation: false
+      capabilities:
+        drop: [ALL]
+      readOnlyRootFilesystem: true
+    resources:
+      limits: {cpu: 250m, memory: 64Mi}
+      requests:
+        cpu: 250m # kube-ai: placeholder, size for the workload
+        memory: 64Mi # kube-ai: placeholder, size for the workload
+  securityContext:
+    seccompProfile:
+      type: RuntimeDefault
+`,
+			changes: []string{
+				"pod/legacy spec.securityContext.seccompProfile",
+				"pod/legacy spec.containers[0].securityContext.allowPrivilegeEscalation",
+				"pod/legacy spec.containers[0].securityContext.capabilities.drop",
+				"pod/legacy spec.containers[0].securityContext.readOnlyRootFilesystem",
+				"pod/legacy spec.containers[0].resources.requests.cpu",
+				"pod/legacy spec.containers[0].resources.requests.memory",
+			},
+		},
+		{
+			name: "unknown user gets a TODO and placeholders",
+			manifest: `apiVersion: v1
+kind: Pod
+metadata:
+  name: app
+spec:
+  # Pod-wide settings
+  securityContext:
+    fsGroup: 2000
+  containers:
+    - name: app
+      image: app:1
+`,
+			want: `apiVersion: v1
+kind: Pod
+metadata:
+  name: app
+spec:
+  # Pod-wide settings
+  # kube-ai: TODO set runAsNonRoot: true and a non-zero runAsUser once the image runs as non-root
+  securityContext:
+    fsGroup: 2000
+    seccompProfile:
+      type: RuntimeDefault
+  containers:
+    - name: app
+      image: app:1
+      securityContext:
+        allowPrivilegeEscalation: false
+        capabilities:
+          drop: [ALL]
+        readOnlyRootFilesystem: true
+      resources:
+        requests:
+          cpu: 100m # kube-ai: placeholder, size for the workload
+          memory: 128Mi # kube-ai: placeholder, size for the workload
+        limits:
+          cpu: 500m # kube-ai: placeholder, size for the workload
+          memory: 256Mi # kube-ai: placeholder, size for the workload
+`,
+		},
+		{
+			name: "privileged and SYS_ADMIN containers keep privilege escalation",
+			manifest: `apiVersion: apps/v1
+kind: DaemonSet
+metadata:
+  name: agent
+spec:
+  template:
+    spec:
+      securityContext:
+        runAsNonRoot: false
+        seccompProfile: {type: RuntimeDefault}
+      containers:
+      - name: driver
+        image: driver:1
+        securityContext:
+          privileged: true
+        resources:
+          requests: {cpu: 100m, memory: 64Mi}
+          limits: {cpu: 100m, memory: 64Mi}
+      - name: mounter
+        image: mounter:1
+        securityContext:
+          capabilities:
+            add: [CAP_SYS_ADMIN]
+        resources:
+          requests: {cpu: 100m, memory: 64Mi}
+          limits: {cpu: 100m, memory: 64Mi}
+`,
+			want: `apiVersion: apps/v1
+kind: DaemonSet
+metadata:
+  name: agent
+spec:
+  template:
+    spec:
+      securityContext:
+        runAsNonRoot: false
+        seccompProfile: {type: RuntimeDefault}
+      containers:
+      - name: driver
+        image: driver:1
+        securityContext:
+          privileged: true
+          readOnlyRootFilesystem: true
+        resources:
+          requests: {cpu: 100m, memory: 64Mi}
+          limits: {cpu: 100m, memory: 64Mi}
+      - name: mounter
+        image: mounter:1
+        securityContext:
+          capabilities:
+            add: [CAP_SYS_ADMIN]
+          readOnlyRootFilesystem: true
+        resources:
+          requests: {cpu: 100m, memory: 64Mi}
+          limits: {cpu: 100m, memory: 64Mi}
+`,
+			changes: []string{
+				"daemonset/agent spec.template.spec.containers[0].securityContext.readOnlyRootFilesystem",
+				"daemonset/agent spec.template.spec.containers[1].securityContext.readOnlyRootFilesystem",
+			},
+		},
+		{
+			name: "known digests are pinned",
+			manifest: `apiVersion: v1
+kind: Pod
+metadata:
+  name: cache
+spec:
+  securityContext:
+    runAsNonRoot: true
+    seccompProfile: {type: RuntimeDefault}
+  containers:
+  - name: redis
+    image: docker.io/library/redis
+    securityContext:
+      allowPrivilegeEscalation: false
+      capabilities: {drop: [ALL]}
+      readOnlyRootFilesystem: true
+    resources:
+      requests: {cpu: 100m, memory: 64Mi}
+      limits: {cpu: 100m, memory: 64Mi}
+  - name: pinned
+    image: nginx:1.27@sha256:1111
+    securityContext:
+      allowPrivilegeEscalation: false
+      capabilities: {drop: [ALL]}
+      readOnlyRootFilesystem: true
+    resources:
+      requests: {cpu: 100m, memory: 64Mi}
+      limits: {cpu: 100m, memory: 64Mi}
+`,
+			digests: map[string]string{"redis:latest": "sha256:abcd", "nginx:1.27": "sha256:2222"},
+			want: `apiVersion: v1
+kind: Pod
+metadata:
+  name: cache
+spec:
+  securityContext:
+    runAsNonRoot: true
+    seccompProfile: {type: RuntimeDefault}
+  containers:
+  - name: redis
+    image: docker.io/library/redis@sha256:abcd
+    securityContext:
+      allowPrivilegeEscalation: false
+      capabilities: {drop: [ALL]}
+      readOnlyRootFilesystem: true
+    resources:
+      requests: {cpu: 100m, memory: 64Mi}
+      limits: {cpu: 100m, memory: 64Mi}
+  - name: pinned
+    image: nginx:1.27@sha256:1111
+    securityContext:
+      allowPrivilegeEscalation: false
+      capabilities: {drop: [ALL]}
+      readOnlyRootFilesystem: true
+    resources:
+      requests: {cpu: 100m, memory: 64Mi}
+      limits: {cpu: 100m, memory: 64Mi}
+`,
+			changes: []string{"pod/cache spec.containers[0].image"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, changes, err := fixManifest(tt.manifest, tt.digests)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("fixed manifest:\n%s\nwant:\n%s", got, tt.want)
+			}
+			if tt.changes != nil {
+				var paths []string
+				for _, c := range changes {
+					paths = append(paths, c.Resource+" "+c.FieldPath)
+				}
+				if !slices.Equal(paths, tt.changes) {
+					t.Errorf("changes:\n  got  %q\n  want %q", paths, tt.changes)
+				}
+			}
+
+			// İkinci çalıştırma hiçbir şeyi değiştirmemeli
+			again, changes, err := fixManifest(got, tt.digests)
+			if err != nil || len(changes) != 0 || again != got {
+				t.Errorf("fix is not idempotent: %d more changes (err %v):\n%s", len(changes), err, again)
+			}
+		})
+	}
+}
+
+func TestFixManifestErrors(t *testing.T) {
+	source := "kind: ConfigMap\nmetadata: {name: settings}\ndata: {mode: fast}\n"
+	got, changes, err := fixManifest(source, nil)
+	if err != nil || got != source || len(changes) != 0 {
+		t.Errorf("manifest without pods: %d changes, err %v:\n%s", len(changes), err, got)
+	}
+	if _, _, err := fixManifest("kind: Pod\nspec: [\n", nil); ExitCode(err) != ExitUsage {
+		t.Errorf("invalid YAML: err %v, want a usage error", err)
+	}
+}
+
+func TestNormalizeImage(t *testing.T) {
+	tests := map[string]string{
+		"nginx":                           "nginx:latest",
+		"docker.io/library/nginx:1.27":    "nginx:1.27",
+		"index.docker.io/bitnami/redis:7": "bitnami/redis:7",
+		"registry.local:5000/team/app":    "registry.local:5000/team/app:latest",
+		"ghcr.io/org/app:2@sha256:aaaa":   "ghcr.io/org/app:2",
+	}
+	for image, want := range tests {
+		if got := normalizeImage(image); got != want {
+			t.Errorf("normalizeImage(%q) = %q, want %q", image, got, want)
+		}
+	}
+}
+
+func TestImageDigests(t *testing.T) {
+	pod := func(name, image, imageID string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "main", Image: image}}},
+			Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{{
+				Name:    "main",
+				Image:   "docker.io/library/" + image,
+				ImageID: imageID,
+			}}},
+		}
+	}
+	cluster, _ := newTestCluster(t,
+		pod("redis-0", "redis:7", "docker.io/library/redis@sha256:aaaa"),
+		pod("redis-1", "redis:7", "docker.io/library/redis@sha256:aaaa"),
+		// Aynı imaj iki farklı digest ile çalışıyor; hangisinin doğru olduğu bilinemez
+		pod("app-0", "app:1", "docker.io/library/app@sha256:bbbb"),
+		pod("app-1", "app:1", "docker.io/library/app@sha256:cccc"),
+		pod("local", "tool:1", "sha256:dddd"),
+	)
+
+	digests, err := cluster.ImageDigests(context.Background(), []string{""})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(digests) != 1 || digests["redis:7"] != "sha256:aaaa" {
+		t.Errorf("digests = %v, want only redis:7 → sha256:aaaa", digests)
+	}
+}
@@ -1,10 +1,8 @@
 package cmd
 
 import (
-	"context"
 	"fmt"
 	"os"
-	"regexp"
 	"strings"
 
 	openai "github.com/sashabaranov/go-openai"
@@ -24,11 +22,10 @@ var GenerateCmd = &cobra.Command{
 	Short: "Generate Kubernetes YAML manifest using AI",
 	Long:  "Use AI to generate Kubernetes YAML manifests (Deployments, StatefulSets, DaemonSets, Services, etc.) based on user description. You can specify additional parameters like namespace, replicas, and metadata name.",
 	Args:  cobra.MinimumNArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		apiKey := os.Getenv("OPENAI_API_KEY")
-		if apiKey == "" {
-			fmt.Println("❌ OPENAI_API_KEY environment variable not set.")
-			return
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := NewProvider()
+		if err != nil {
+			return err
 		}
 
 		basePrompt := strings.Join(args, " ")
@@ -43,10 +40,14 @@ var GenerateCmd = &cobra.Command{
 			extraPrompt += fmt.Sprintf(" Set metadata name to '%s'.", customName)
 		}
 
-		finalPrompt := fmt.Sprintf(
-			"%s%s ONLY return raw Kubernetes YAML. Do not include any titles, explanations, or code blocks.",
-			basePrompt, extraPrompt,
-		)
+		systemPrompt, finalPrompt, err := renderPrompts("generate", PromptData{
+			Resource:  customName,
+			Namespace: customNamespace,
+			Question:  basePrompt + extraPrompt,
+		})
+		if err != nil {
+			return err
+		}
 
 		// History’e kaydet
 		SaveToHistory("generate", fmt.Sprintf(
@@ -54,35 +55,31 @@ var GenerateCmd = &cobra.Command{
 			basePrompt, customNamespace, customReplicas, customName, saveToFile, outputFile,
 		))
 
-		client := openai.NewClient(apiKey)
-		resp, err := client.CreateChatCompletion(
-			context.Background(),
-			openai.ChatCompletionRequest{
-				Model: Model,
-				Messages: []openai.ChatCompletionMessage{
-					{
-						Role: openai.ChatMessageRoleSystem,
-						Content: `You are a Kubernetes YAML generator.
-Return only raw YAML manifests without any markdown, code blocks, or titles.
-Do not include any text like 'Deployment manifest', 'Service manifest', or 'yaml'. Only valid YAML content.`,
-					},
-					{
-						Role:    openai.ChatMessageRoleUser,
-						Content: finalPrompt,
-					},
+		// Sonucu yazdır; fence satırları stream sırasında da atılır
+		fmt.Println("\n📄 Generated Kubernetes YAML:")
+		fmt.Println("-----------------------------------")
+		resp, err := complete(cmd.Context(), client, openai.ChatCompletionRequest{
+			Model: Model,
+			Messages: []openai.ChatCompletionMessage{
+				{
+					Role:    openai.ChatMessageRoleSystem,
+					Content: systemPrompt,
+				},
+				{
+					Role:    openai.ChatMessageRoleUser,
+					Content: finalPrompt,
 				},
-				MaxTokens: MaxTokens,
 			},
-		)
+			MaxTokens: MaxTokens,
+		}, &fenceFilter{out: os.Stdout})
 		if err != nil {
-			fmt.Println("❌ OpenAI error:", err)
-			return
+			return err
 		}
+		fmt.Println("-----------------------------------")
 
 		output := strings.TrimSpace(resp.Choices[0].Message.Content)
 
 		// Sadece ``` veya ```yaml fence satırlarını at, geri kalanı olduğu gibi bırak
-		fenceRe := regexp.MustCompile("(?i)^```(?:yaml)?$")
 		var cleaned []string
 		for _, line := range strings.Split(output, "\n") {
 			if fenceRe.MatchString(strings.TrimSpace(line)) {
@@ -92,11 +89,7 @@ Do not include any text like 'Deployment manifest', 'Service manifest', or 'yaml
 		}
 		output = strings.TrimSpace(strings.Join(cleaned, "\n"))
 
-		// Sonucu yazdır
-		fmt.Println("\n📄 Generated Kubernetes YAML:")
-		fmt.Println("-----------------------------------")
-		fmt.Println(output)
-		fmt.Println("-----------------------------------")
+		printUsage(resp.Model, resp.Usage)
 
 		// Dosyaya kaydetme
 		if saveToFile {
@@ -105,11 +98,11 @@ Do not include any text like 'Deployment manifest', 'Service manifest', or 'yaml
 				file = outputFile
 			}
 			if err := os.WriteFile(file, []byte(output), 0644); err != nil {
-				fmt.Println("❌ Failed to save YAML to file:", err)
-				return
+				return fmt.Errorf("failed to save YAML to file: %w", err)
 			}
 			fmt.Println("✅ YAML saved to file:", file)
 		}
+		return nil
 	},
 }
 
@@ -31,21 +31,21 @@ var HistoryCmd = &cobra.Command{
 	Use:   "history",
 	Short: "Show command history",
 	Long:  "Displays previously used kube-ai commands stored in ~/.kube-ai-history.",
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		home, err := os.UserHomeDir()
 		if err != nil {
-			fmt.Println("❌ Could not detect home directory:", err)
-			return
+			return fmt.Errorf("could not detect home directory: %w", err)
 		}
 
 		historyPath := filepath.Join(home, ".kube-ai-history")
 		data, err := os.ReadFile(historyPath)
 		if err != nil {
 			fmt.Println("ℹ️ No history found yet.")
-			return
+			return nil
 		}
 
 		fmt.Println("📜 Command History:")
 		fmt.Println(string(data))
+		return nil
 	},
 }
@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"os"
+	"strings"
+	"unicode/utf16"
+)
+
+var (
+	bomUTF8    = []byte{0xEF, 0xBB, 0xBF}
+	bomUTF16LE = []byte{0xFF, 0xFE}
+	bomUTF16BE = []byte{0xFE, 0xFF}
+)
+
+// readInput, -f ile verilen dosyayı okur; "-" standart girdiyi okur. PowerShell
+// yönlendirmesinin ürettiği UTF-16 ve BOM'lu UTF-8 içerik UTF-8'e çevrilir.
+func readInput(path string) (string, error) {
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return "", usageErrorf("failed to read file %s: %w", inputName(path), err)
+	}
+	// Windows satır sonları da sadeleştirilir
+	return strings.ReplaceAll(decodeText(data), "\r\n", "\n"), nil
+}
+
+// inputName, rapor ve geçmiş kayıtlarında kullanılan girdi adıdır.
+func inputName(path string) string {
+	if path == "-" {
+		return "stdin"
+	}
+	return path
+}
+
+// decodeText, BOM'a (veya BOM'suz UTF-16'da sıfır baytlara) bakarak kodlamayı
+// belirler ve metni UTF-8 olarak döndürür.
+func decodeText(data []byte) string {
+	switch {
+	case bytes.HasPrefix(data, bomUTF8):
+		return string(data[len(bomUTF8):])
+	case bytes.HasPrefix(data, bomUTF16LE):
+		return decodeUTF16(data[len(bomUTF16LE):], binary.LittleEndian)
+	case bytes.HasPrefix(data, bomUTF16BE):
+		return decodeUTF16(data[len(bomUTF16BE):], binary.BigEndian)
+	}
+
+	// BOM'suz UTF-16: ASCII ağırlıklı metinde her ikinci bayt sıfırdır
+	if len(data) >= 4 && bytes.Count(data, []byte{0}) > len(data)/4 {
+		if data[0] != 0 && data[1] == 0 {
+			return decodeUTF16(data, binary.LittleEndian)
+		}
+		if data[0] == 0 && data[1] != 0 {
+			return decodeUTF16(data, binary.BigEndian)
+		}
+	}
+	return string(data)
+}
+
+func decodeUTF16(data []byte, order binary.ByteOrder) string {
+	units := make([]uint16, len(data)/2)
+	for i := range units {
+		units[i] = order.Uint16(data[2*i:])
+	}
+	return string(utf16.Decode(units))
+}
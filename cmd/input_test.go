@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+	"unicode/utf16"
+)
+
+// encodeUTF16, metni verilen bayt sırasıyla UTF-16'ya çevirir.
+func encodeUTF16(text string, order binary.ByteOrder) []byte {
+	units := utf16.Encode([]rune(text))
+	out := make([]byte, 2*len(units))
+	for i, unit := range units {
+		order.PutUint16(out[2*i:], unit)
+	}
+	return out
+}
+
+func TestDecodeText(t *testing.T) {
+	const manifest = "apiVersion: v1\nkind: ConfigMap\nmetadata: {name: ayarlar}\ndata: {note: \"çalışıyor 🚀\"}\n"
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"plain UTF-8", []byte(manifest)},
+		{"UTF-8 with BOM", append([]byte{0xEF, 0xBB, 0xBF}, manifest...)},
+		{"UTF-16 LE with BOM", append([]byte{0xFF, 0xFE}, encodeUTF16(manifest, binary.LittleEndian)...)},
+		{"UTF-16 BE with BOM", append([]byte{0xFE, 0xFF}, encodeUTF16(manifest, binary.BigEndian)...)},
+		{"UTF-16 LE without BOM", encodeUTF16(manifest, binary.LittleEndian)},
+		{"UTF-16 BE without BOM", encodeUTF16(manifest, binary.BigEndian)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := decodeText(tt.data); got != manifest {
+				t.Errorf("decodeText = %q, want %q", got, manifest)
+			}
+		})
+	}
+
+	// Tek bir NUL içeren UTF-8 metin UTF-16 sanılmamalı
+	binaryish := "a\x00bcdefgh"
+	if got := decodeText([]byte(binaryish)); got != binaryish {
+		t.Errorf("decodeText(%q) = %q", binaryish, got)
+	}
+}
+
+func TestReadInputNormalizesLineEndings(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pod.yaml")
+	data := append([]byte{0xFF, 0xFE}, encodeUTF16("kind: Pod\r\nmetadata: {name: web}\r\n", binary.LittleEndian)...)
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	got, err := readInput(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "kind: Pod\nmetadata: {name: web}\n"; got != want {
+		t.Errorf("readInput = %q, want %q", got, want)
+	}
+	if _, err := readInput(filepath.Join(t.TempDir(), "missing.yaml")); ExitCode(err) != ExitUsage {
+		t.Errorf("missing file: err = %v, want a usage error", err)
+	}
+}
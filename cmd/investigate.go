@@ -0,0 +1,361 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	openai "github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/jsonschema"
+)
+
+// Bir araç çıktısından modele gönderilecek en fazla karakter sayısı.
+const maxToolOutput = 16000
+
+// droppedToolOutput, context penceresine sığmak için atılan araç çıktısının yerine konur.
+const droppedToolOutput = "[output dropped to fit the model's context window; call the tool again if you still need it]"
+
+// truncatedToolOutput, kısaltılan en yeni araç çıktısının başına eklenir.
+const truncatedToolOutput = "[output truncated to fit the model's context window]\n"
+
+// Araç argümanlarında izin verilen karakterler (Kubernetes isimleri ve tür adları).
+var safeArgRe = regexp.MustCompile(`^[A-Za-z0-9._:/-]*$`)
+
+// toolArgs, modelin araç çağrılarında gönderdiği argümanlar.
+type toolArgs struct {
+	Resource  string `json:"resource"`
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	Container string `json:"container"`
+	Previous  bool   `json:"previous"`
+	Tail      int    `json:"tail"`
+}
+
+// kubeTool, modelin çağırabileceği salt okunur bir cluster sorgusu.
+type kubeTool struct {
+	definition openai.FunctionDefinition
+	// label, kanıt listesinde gösterilen kubectl eşdeğeri komutu üretir.
+	label func(a toolArgs, namespace string) string
+	run   func(ctx context.Context, c *Cluster, a toolArgs, namespace string) (string, error)
+}
+
+var kubeTools = map[string]kubeTool{
+	"k8s_get": {
+		definition: openai.FunctionDefinition{
+			Name:        "k8s_get",
+			Description: "Read the spec and status of a resource as YAML (like 'kubectl get <resource> <name> -o yaml'), or list resources of a type when name is omitted.",
+			Parameters: jsonschema.Definition{
+				Type: jsonschema.Object,
+				Properties: map[string]jsonschema.Definition{
+					"resource":  {Type: jsonschema.String, Description: "Resource type, e.g. pod, deployment, replicaset, node, pvc"},
+					"name":      {Type: jsonschema.String, Description: "Resource name; omit to list all resources of the type"},
+					"namespace": {Type: jsonschema.String, Description: "Namespace; defaults to the namespace under investigation"},
+				},
+				Required: []string{"resource"},
+			},
+		},
+		label: func(a toolArgs, namespace string) string {
+			if a.Name == "" {
+				return fmt.Sprintf("kubectl get %s -n %s", a.Resource, namespace)
+			}
+			return fmt.Sprintf("kubectl get %s %s -n %s -o yaml", a.Resource, a.Name, namespace)
+		},
+		run: func(ctx context.Context, c *Cluster, a toolArgs, namespace string) (string, error) {
+			if a.Resource == "" {
+				return "", fmt.Errorf("resource is required")
+			}
+			if a.Name == "" {
+				return c.List(ctx, a.Resource, namespace)
+			}
+			return c.GetYAML(ctx, a.Resource, a.Name, namespace)
+		},
+	},
+	"k8s_describe": {
+		definition: openai.FunctionDefinition{
+			Name:        "k8s_describe",
+			Description: "Read a resource together with the events that involve it (like 'kubectl describe <resource> <name>').",
+			Parameters: jsonschema.Definition{
+				Type: jsonschema.Object,
+				Properties: map[string]jsonschema.Definition{
+					"resource":  {Type: jsonschema.String, Description: "Resource type, e.g. pod, deployment, node"},
+					"name":      {Type: jsonschema.String, Description: "Resource name"},
+					"namespace": {Type: jsonschema.String, Description: "Namespace; defaults to the namespace under investigation"},
+				},
+				Required: []string{"resource", "name"},
+			},
+		},
+		label: func(a toolArgs, namespace string) string {
+			return fmt.Sprintf("kubectl describe %s %s -n %s", a.Resource, a.Name, namespace)
+		},
+		run: func(ctx context.Context, c *Cluster, a toolArgs, namespace string) (string, error) {
+			if a.Resource == "" || a.Name == "" {
+				return "", fmt.Errorf("resource and name are required")
+			}
+			return c.Describe(ctx, a.Resource, a.Name, namespace)
+		},
+	},
+	"k8s_logs": {
+		definition: openai.FunctionDefinition{
+			Name:        "k8s_logs",
+			Description: "Read the logs of a pod. Set previous=true to read logs of the last terminated container (useful for CrashLoopBackOff).",
+			Parameters: jsonschema.Definition{
+				Type: jsonschema.Object,
+				Properties: map[string]jsonschema.Definition{
+					"name":      {Type: jsonschema.String, Description: "Pod name"},
+					"namespace": {Type: jsonschema.String, Description: "Namespace; defaults to the namespace under investigation"},
+					"container": {Type: jsonschema.String, Description: "Container name for multi-container pods"},
+					"previous":  {Type: jsonschema.Boolean, Description: "Read logs of the previous container instance"},
+					"tail":      {Type: jsonschema.Integer, Description: "Number of lines from the end (default 200)"},
+				},
+				Required: []string{"name"},
+			},
+		},
+		label: func(a toolArgs, namespace string) string {
+			label := fmt.Sprintf("kubectl logs %s -n %s --tail %d", a.Name, namespace, toolTail(a.Tail))
+			if a.Container != "" {
+				label += " -c " + a.Container
+			}
+			if a.Previous {
+				label += " --previous"
+			}
+			return label
+		},
+		run: func(ctx context.Context, c *Cluster, a toolArgs, namespace string) (string, error) {
+			if a.Name == "" {
+				return "", fmt.Errorf("name is required")
+			}
+			return c.Logs(ctx, a.Name, namespace, a.Container, a.Previous, int64(toolTail(a.Tail)))
+		},
+	},
+	"k8s_events": {
+		definition: openai.FunctionDefinition{
+			Name:        "k8s_events",
+			Description: "List events in a namespace sorted by time, optionally only those involving one object.",
+			Parameters: jsonschema.Definition{
+				Type: jsonschema.Object,
+				Properties: map[string]jsonschema.Definition{
+					"name":      {Type: jsonschema.String, Description: "Only events whose involved object has this name"},
+					"namespace": {Type: jsonschema.String, Description: "Namespace; defaults to the namespace under investigation"},
+				},
+			},
+		},
+		label: func(a toolArgs, namespace string) string {
+			label := fmt.Sprintf("kubectl get events -n %s --sort-by=.lastTimestamp", namespace)
+			if a.Name != "" {
+				label += " --field-selector involvedObject.name=" + a.Name
+			}
+			return label
+		},
+		run: func(ctx context.Context, c *Cluster, a toolArgs, namespace string) (string, error) {
+			return c.Events(ctx, namespace, a.Name)
+		},
+	},
+	"k8s_top": {
+		definition: openai.FunctionDefinition{
+			Name:        "k8s_top",
+			Description: "Show current CPU and memory usage of pods or nodes (like 'kubectl top'; requires metrics-server).",
+			Parameters: jsonschema.Definition{
+				Type: jsonschema.Object,
+				Properties: map[string]jsonschema.Definition{
+					"resource":  {Type: jsonschema.String, Enum: []string{"pods", "nodes"}},
+					"name":      {Type: jsonschema.String, Description: "Optional pod or node name"},
+					"namespace": {Type: jsonschema.String, Description: "Namespace for pods; defaults to the namespace under investigation"},
+				},
+				Required: []string{"resource"},
+			},
+		},
+		label: func(a toolArgs, namespace string) string {
+			if strings.HasPrefix(a.Resource, "node") {
+				return strings.TrimSpace("kubectl top node " + a.Name)
+			}
+			return strings.TrimSpace(fmt.Sprintf("kubectl top pod %s -n %s --containers", a.Name, namespace))
+		},
+		run: func(ctx context.Context, c *Cluster, a toolArgs, namespace string) (string, error) {
+			return c.Top(ctx, a.Resource, a.Name, namespace)
+		},
+	},
+}
+
+// toolTail, modelin istediği log satır sayısını makul bir aralıkta tutar.
+func toolTail(tail int) int {
+	if tail <= 0 || tail > 1000 {
+		return 200
+	}
+	return tail
+}
+
+func kubeToolDefinitions() []openai.Tool {
+	names := []string{"k8s_get", "k8s_describe", "k8s_logs", "k8s_events", "k8s_top"}
+	tools := make([]openai.Tool, 0, len(names))
+	for _, name := range names {
+		def := kubeTools[name].definition
+		tools = append(tools, openai.Tool{Type: openai.ToolTypeFunction, Function: &def})
+	}
+	return tools
+}
+
+// runKubeTool, modelin istediği aracı çalıştırır ve (kubectl eşdeğeri komut, çıktı) döndürür.
+// Hatalar da modele çıktı olarak iletilir ki bir sonraki adımı ona göre seçsin.
+func runKubeTool(ctx context.Context, cluster *Cluster, call openai.ToolCall, defaultNamespace string) (string, string) {
+	tool, ok := kubeTools[call.Function.Name]
+	if !ok {
+		return call.Function.Name, fmt.Sprintf("error: unknown tool %q", call.Function.Name)
+	}
+
+	var a toolArgs
+	if call.Function.Arguments != "" {
+		if err := json.Unmarshal([]byte(call.Function.Arguments), &a); err != nil {
+			return call.Function.Name, fmt.Sprintf("error: invalid arguments: %v", err)
+		}
+	}
+	for _, v := range []string{a.Resource, a.Name, a.Namespace, a.Container} {
+		if !safeArgRe.MatchString(v) {
+			return call.Function.Name, fmt.Sprintf("error: invalid argument value %q", v)
+		}
+	}
+
+	namespace := a.Namespace
+	if namespace == "" {
+		namespace = defaultNamespace
+	}
+	command := tool.label(a, namespace)
+	if Verbose {
+		fmt.Println("🔧", command)
+	}
+
+	result, err := tool.run(ctx, cluster, a, namespace)
+	if err != nil {
+		result = fmt.Sprintf("error: %v", err)
+	}
+	if len(result) > maxToolOutput {
+		result = result[len(result)-maxToolOutput:] + "\n[output truncated to the last 16000 characters]"
+	}
+	return command, result
+}
+
+// investigationPrompt, çok adımlı incelemede sistem mesajına eklenir.
+const investigationPrompt = `
+
+You can call read-only Kubernetes tools to gather more evidence before answering.
+Only call a tool when the data you already have is not enough to find the root cause.
+When you are done, give the final answer and end it with a section titled "Evidence used" that lists the outputs you relied on.`
+
+// investigate, modelin araç çağırarak kanıt toplayabildiği en fazla MaxIterations
+// adımlık bir döngü çalıştırır. Cevap out'a yazılır; son yanıt (toplam token
+// kullanımıyla) ve çalıştırılan komutlar döndürülür. cluster nil ise (cluster hedeflenmiyorsa)
+// veya MaxIterations <= 1 ise araç tanımlanmaz ve tek bir istek yapılır.
+func investigate(ctx context.Context, client Provider, cluster *Cluster, systemPrompt, userPrompt, namespace string, out io.Writer) (openai.ChatCompletionResponse, []string, error) {
+	var tools []openai.Tool
+	if cluster != nil && MaxIterations > 1 {
+		tools = kubeToolDefinitions()
+		systemPrompt += investigationPrompt
+	}
+
+	messages := []openai.ChatCompletionMessage{
+		{Role: openai.ChatMessageRoleSystem, Content: systemPrompt},
+		{Role: openai.ChatMessageRoleUser, Content: userPrompt},
+	}
+
+	var evidence []string
+	var usage openai.Usage
+	budget := inputBudget(systemPrompt)
+	for step := 1; ; step++ {
+		if dropped := fitToolOutputs(messages, budget); dropped > 0 && Verbose {
+			fmt.Fprintf(statusOut, "✂️ Dropped %d older tool output(s) to stay within the ~%d-token context of model %s\n", dropped, lookupContextSize(Model), Model)
+		}
+		req := openai.ChatCompletionRequest{
+			Model:     Model,
+			Messages:  messages,
+			MaxTokens: MaxTokens,
+			Tools:     tools,
+		}
+		// Son adımda modeli cevap vermeye zorla
+		if tools != nil && step >= MaxIterations {
+			req.ToolChoice = "none"
+		}
+
+		resp, err := complete(ctx, client, req, out)
+		if err != nil {
+			return resp, evidence, err
+		}
+		usage = addUsage(usage, resp.Usage)
+
+		msg := resp.Choices[0].Message
+		if len(msg.ToolCalls) == 0 || step >= MaxIterations {
+			resp.Usage = usage
+			return resp, evidence, nil
+		}
+
+		messages = append(messages, msg)
+		for _, call := range msg.ToolCalls {
+			command, output := runKubeTool(ctx, cluster, call, namespace)
+			evidence = append(evidence, command)
+			messages = append(messages, openai.ChatCompletionMessage{
+				Role:       openai.ChatMessageRoleTool,
+				Content:    output,
+				ToolCallID: call.ID,
+			})
+		}
+	}
+}
+
+// fitToolOutputs, konuşma budget token'ı aşıyorsa en eski araç çıktılarını
+// droppedToolOutput ile değiştirir; en yeni çıktı tek başına sığmıyorsa sonu
+// korunarak kısaltılır. Mesajlar yerinde değiştirilir ve atılan çıktı sayısı döner.
+// Araç mesajlarının kendisi silinmez, çünkü API her tool_call için bir cevap bekler.
+func fitToolOutputs(messages []openai.ChatCompletionMessage, budget int) int {
+	total := 0
+	var tools []int
+	for i, m := range messages {
+		// Sistem prompt'u budget'tan zaten düşülmüştür
+		if m.Role == openai.ChatMessageRoleSystem {
+			continue
+		}
+		total += estimateTokens(m.Content)
+		for _, call := range m.ToolCalls {
+			total += estimateTokens(call.Function.Name + call.Function.Arguments)
+		}
+		if m.Role == openai.ChatMessageRoleTool && m.Content != droppedToolOutput {
+			tools = append(tools, i)
+		}
+	}
+
+	dropped := 0
+	for n, i := range tools {
+		if total <= budget {
+			break
+		}
+		m := &messages[i]
+		if n == len(tools)-1 {
+			// En yeni çıktı modelin az önce istediği veridir; atmak yerine sonu korunur
+			keep := (budget-total+estimateTokens(m.Content)-1)*charsPerToken - len(truncatedToolOutput)
+			if keep > 0 && keep < len(m.Content) {
+				start := len(m.Content) - keep
+				for start < len(m.Content) && !utf8.RuneStart(m.Content[start]) {
+					start++
+				}
+				m.Content = truncatedToolOutput + m.Content[start:]
+				break
+			}
+		}
+		total += estimateTokens(droppedToolOutput) - estimateTokens(m.Content)
+		m.Content = droppedToolOutput
+		dropped++
+	}
+	return dropped
+}
+
+// printEvidence, inceleme sırasında çalıştırılan komutları listeler.
+func printEvidence(evidence []string) {
+	if len(evidence) == 0 {
+		return
+	}
+	fmt.Println("\n🔎 Evidence collected:")
+	for _, command := range evidence {
+		fmt.Println("  -", command)
+	}
+}
@@ -0,0 +1,158 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	openai "github.com/sashabaranov/go-openai"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func toolMessage(content string) openai.ChatCompletionMessage {
+	return openai.ChatCompletionMessage{Role: openai.ChatMessageRoleTool, Content: content, ToolCallID: "call"}
+}
+
+func TestFitToolOutputs(t *testing.T) {
+	big := strings.Repeat("x", 4000) // ~1000 token
+	tests := []struct {
+		name        string
+		tools       []string
+		budget      int
+		wantDropped int
+		want        []string // araç mesajlarının beklenen içerikleri; "tail" kısaltılmış çıktıdır
+	}{
+		{"fits", []string{"a", "b"}, 1000, 0, []string{"a", "b"}},
+		{"oldest outputs are dropped first", []string{big, big, "newest"}, 800, 2, []string{droppedToolOutput, droppedToolOutput, "newest"}},
+		{"only as many as needed", []string{big, big, big}, 2200, 1, []string{droppedToolOutput, big, big}},
+		{"newest output is truncated, not dropped", []string{big, big}, 500, 1, []string{droppedToolOutput, "tail"}},
+		{"no room at all", []string{big}, 0, 1, []string{droppedToolOutput}},
+		{"dropped outputs stay dropped", []string{droppedToolOutput, big}, 2000, 0, []string{droppedToolOutput, big}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			messages := []openai.ChatCompletionMessage{
+				{Role: openai.ChatMessageRoleSystem, Content: big},
+				{Role: openai.ChatMessageRoleUser, Content: "why is web failing?"},
+			}
+			for _, content := range tt.tools {
+				messages = append(messages, openai.ChatCompletionMessage{
+					Role:      openai.ChatMessageRoleAssistant,
+					ToolCalls: []openai.ToolCall{{ID: "call", Function: openai.FunctionCall{Name: "k8s_get", Arguments: `{"resource":"pod"}`}}},
+				}, toolMessage(content))
+			}
+
+			if dropped := fitToolOutputs(messages, tt.budget); dropped != tt.wantDropped {
+				t.Errorf("dropped = %d, want %d", dropped, tt.wantDropped)
+			}
+			var got []string
+			for _, m := range messages {
+				if m.Role != openai.ChatMessageRoleTool {
+					continue
+				}
+				if strings.HasPrefix(m.Content, truncatedToolOutput) {
+					got = append(got, "tail")
+					if !strings.HasSuffix(big, strings.TrimPrefix(m.Content, truncatedToolOutput)) {
+						t.Error("truncated output does not keep the end of the output")
+					}
+					continue
+				}
+				got = append(got, m.Content)
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("tool outputs = %.80q, want %.80q", got, tt.want)
+			}
+			if tt.budget > 0 {
+				if total := conversationTokens(messages); total > tt.budget {
+					t.Errorf("conversation is ~%d tokens, over the budget of %d", total, tt.budget)
+				}
+			}
+		})
+	}
+}
+
+// conversationTokens, sistem prompt'u dışındaki mesajların tahmini token sayısıdır.
+func conversationTokens(messages []openai.ChatCompletionMessage) int {
+	total := 0
+	for _, m := range messages[1:] {
+		total += estimateTokens(m.Content)
+		for _, call := range m.ToolCalls {
+			total += estimateTokens(call.Function.Name + call.Function.Arguments)
+		}
+	}
+	return total
+}
+
+// toolLoopProvider, son adıma kadar her istekte aynı aracı çağırır ve her
+// isteğin konuşma boyutunu kaydeder.
+type toolLoopProvider struct {
+	requests []openai.ChatCompletionRequest
+}
+
+func (p *toolLoopProvider) CreateChatCompletion(ctx context.Context, req openai.ChatCompletionRequest) (openai.ChatCompletionResponse, error) {
+	p.requests = append(p.requests, req)
+	msg := openai.ChatCompletionMessage{Role: openai.ChatMessageRoleAssistant}
+	if req.ToolChoice == "none" {
+		msg.Content = "The settings are fine."
+	} else {
+		msg.ToolCalls = []openai.ToolCall{{
+			ID:       fmt.Sprintf("call-%d", len(p.requests)),
+			Type:     openai.ToolTypeFunction,
+			Function: openai.FunctionCall{Name: "k8s_get", Arguments: `{"resource":"configmaps","name":"settings","namespace":"app"}`},
+		}}
+	}
+	return openai.ChatCompletionResponse{Choices: []openai.ChatCompletionChoice{{Message: msg}}}, nil
+}
+
+func (p *toolLoopProvider) CreateChatCompletionStream(context.Context, openai.ChatCompletionRequest) (*openai.ChatCompletionStream, error) {
+	return nil, fmt.Errorf("streaming is not supported")
+}
+
+func TestInvestigateStaysWithinContext(t *testing.T) {
+	quietStatus(t)
+	setModelLimits(t, 4096, 256)
+	previousIterations, previousNoStream := MaxIterations, NoStream
+	MaxIterations, NoStream = 8, true
+	t.Cleanup(func() { MaxIterations, NoStream = previousIterations, previousNoStream })
+
+	cluster, _ := newTestCluster(t, &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "settings", Namespace: "app"},
+		Data:       map[string]string{"config": strings.Repeat("feature.flag=enabled\n", 90)},
+	})
+	client := &toolLoopProvider{}
+	resp, evidence, err := investigate(context.Background(), client, cluster, "You are a Kubernetes expert.", "Why is the app slow?", "app", io.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Choices[0].Message.Content != "The settings are fine." || len(evidence) != MaxIterations-1 {
+		t.Fatalf("answer %q after %d tool calls", resp.Choices[0].Message.Content, len(evidence))
+	}
+
+	budget := inputBudget("You are a Kubernetes expert." + investigationPrompt)
+	dropped := 0
+	for i, req := range client.requests {
+		if total := conversationTokens(req.Messages); total > budget {
+			t.Errorf("request %d is ~%d tokens, over the budget of %d", i+1, total, budget)
+		}
+		// Her tool_call'ın bir cevabı kalmalı
+		calls, results := 0, 0
+		for _, m := range req.Messages {
+			calls += len(m.ToolCalls)
+			if m.Role == openai.ChatMessageRoleTool {
+				results++
+				if m.Content == droppedToolOutput {
+					dropped++
+				}
+			}
+		}
+		if calls != results {
+			t.Errorf("request %d has %d tool calls and %d results", i+1, calls, results)
+		}
+	}
+	if dropped == 0 {
+		t.Error("no tool output was dropped; the test does not fill the context window")
+	}
+}
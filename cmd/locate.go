@@ -0,0 +1,184 @@
+package cmd
+
+import (
+	"strconv"
+	"strings"
+
+	yaml "go.yaml.in/yaml/v3"
+)
+
+// locateFindings, -f ile verilen dosyadaki satır numaralarını bulgulara ekler.
+// Satırlar normalize/redact edilmemiş orijinal metinden hesaplanır ki
+// code-scanning arayüzleri doğru satırı göstersin.
+func locateFindings(findings []Finding, path, source string) {
+	docs := yamlDocuments(source)
+	for i := range findings {
+		f := &findings[i]
+		f.File = path
+		doc := findDocument(docs, f.Resource)
+		// Tek doküman varsa kaynak adı eşleşmese de o kullanılır
+		if doc == nil && len(docs) == 1 {
+			doc = docs[0]
+		}
+		if doc != nil {
+			f.Line = fieldLine(doc, f.FieldPath)
+		}
+	}
+}
+
+// locateFindingsInFiles, birden çok dosyadan okunan girdide her bulguyu
+// kaynağını tanımlayan dosyaya ve satıra bağlar.
+func locateFindingsInFiles(findings []Finding, paths, sources []string) {
+	for i, path := range paths {
+		if path == "-" {
+			continue
+		}
+		docs := yamlDocuments(sources[i])
+		for j := range findings {
+			f := &findings[j]
+			if f.File != "" {
+				continue
+			}
+			if doc := findDocument(docs, f.Resource); doc != nil {
+				f.File, f.Line = path, fieldLine(doc, f.FieldPath)
+			}
+		}
+	}
+}
+
+// yamlDocuments, metindeki YAML dokümanlarını döndürür; List ve
+// items içeren dokümanlar elemanlarına açılır. Okunamayan dokümanlar atlanır,
+// satır numaraları tüm metne göredir.
+func yamlDocuments(source string) []*yaml.Node {
+	var docs []*yaml.Node
+	for _, part := range splitManifest(source) {
+		dec := yaml.NewDecoder(strings.NewReader(part.Text))
+		for {
+			var doc yaml.Node
+			if err := dec.Decode(&doc); err != nil {
+				break
+			}
+			if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+				continue
+			}
+			root := doc.Content[0]
+			shiftLines(root, part.Line-1)
+			if items := mappingValue(root, "items"); items != nil && items.Kind == yaml.SequenceNode {
+				docs = append(docs, items.Content...)
+				continue
+			}
+			docs = append(docs, root)
+		}
+	}
+	return docs
+}
+
+// shiftLines, düğümün ve alt düğümlerinin satır numaralarını offset kadar kaydırır.
+func shiftLines(node *yaml.Node, offset int) {
+	node.Line += offset
+	for _, child := range node.Content {
+		shiftLines(child, offset)
+	}
+}
+
+// findDocument, "kind/name" biçimindeki kaynağa karşılık gelen dokümanı bulur.
+func findDocument(docs []*yaml.Node, resource string) *yaml.Node {
+	kind, name, ok := strings.Cut(resource, "/")
+	if !ok {
+		kind, name = "", resource
+	}
+	for _, doc := range docs {
+		if name != mappingScalar(mappingValue(doc, "metadata"), "name") {
+			continue
+		}
+		if kind == "" || strings.EqualFold(kind, mappingScalar(doc, "kind")) {
+			return doc
+		}
+	}
+	return nil
+}
+
+// fieldLine, spec.containers[0].image gibi bir alan yolunun satırını döndürür.
+// Yolun olmayan kısmına gelindiğinde bulunan en derin alanın satırı kullanılır.
+func fieldLine(doc *yaml.Node, fieldPath string) int {
+	line := doc.Line
+	if key := mappingKey(doc, "kind"); key != nil {
+		line = key.Line
+	}
+	node := doc
+	for _, part := range splitFieldPath(fieldPath) {
+		if index, err := strconv.Atoi(part); err == nil {
+			if node.Kind != yaml.SequenceNode || index < 0 || index >= len(node.Content) {
+				break
+			}
+			node = node.Content[index]
+			line = node.Line
+			continue
+		}
+		key := mappingKey(node, part)
+		if key == nil {
+			break
+		}
+		node = mappingValue(node, part)
+		line = key.Line
+	}
+	return line
+}
+
+// splitFieldPath, "a.b[0].c" yolunu "a", "b", "0", "c" parçalarına ayırır.
+func splitFieldPath(fieldPath string) []string {
+	var parts []string
+	for _, part := range strings.Split(fieldPath, ".") {
+		for part != "" {
+			open := strings.Index(part, "[")
+			if open < 0 {
+				parts = append(parts, part)
+				break
+			}
+			if open > 0 {
+				parts = append(parts, part[:open])
+			}
+			end := strings.Index(part[open:], "]")
+			if end < 0 {
+				break
+			}
+			parts = append(parts, part[open+1:open+end])
+			part = part[open+end+1:]
+		}
+	}
+	return parts
+}
+
+// mappingKey, mapping düğümündeki anahtarın düğümünü döndürür.
+func mappingKey(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i]
+		}
+	}
+	return nil
+}
+
+// mappingValue, mapping düğümündeki anahtarın değerini döndürür.
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// mappingScalar, anahtarın metin değerini döndürür; yoksa boş döner.
+func mappingScalar(node *yaml.Node, key string) string {
+	if value := mappingValue(node, key); value != nil && value.Kind == yaml.ScalarNode {
+		return value.Value
+	}
+	return ""
+}
@@ -20,18 +20,16 @@ var ModifyCmd = &cobra.Command{
 	Use:   "modify --file <yaml> [options]",
 	Short: "Modify a Kubernetes YAML manifest",
 	Long:  "Modify fields like namespace, replicas, and metadata name inside an existing YAML manifest file. It does not apply the changes automatically; you can apply them later using execute.",
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		if modifyFile == "" {
-			fmt.Println("❌ Please specify a YAML file with --file")
-			return
+			return usageErrorf("please specify a YAML file with --file")
 		}
 
 		SaveToHistory("modify", fmt.Sprintf("file=%s ns=%s name=%s replicas=%d", modifyFile, newNamespace, newName, newReplicas))
 
 		data, err := os.ReadFile(modifyFile)
 		if err != nil {
-			fmt.Println("❌ Failed to read YAML file:", err)
-			return
+			return usageErrorf("failed to read YAML file: %w", err)
 		}
 
 		// Çoklu YAML desteği için split
@@ -47,8 +45,7 @@ var ModifyCmd = &cobra.Command{
 			var manifest map[string]interface{}
 			err := yaml.Unmarshal([]byte(doc), &manifest)
 			if err != nil {
-				fmt.Println("❌ Failed to parse YAML:", err)
-				return
+				return usageErrorf("failed to parse YAML: %w", err)
 			}
 
 			// metadata.name ve metadata.namespace güncelle
@@ -70,8 +67,7 @@ var ModifyCmd = &cobra.Command{
 
 			modifiedYAML, err := yaml.Marshal(manifest)
 			if err != nil {
-				fmt.Println("❌ Failed to marshal updated YAML:", err)
-				return
+				return fmt.Errorf("failed to marshal updated YAML: %w", err)
 			}
 			updatedDocs = append(updatedDocs, string(modifiedYAML))
 		}
@@ -80,12 +76,12 @@ var ModifyCmd = &cobra.Command{
 
 		err = os.WriteFile(modifyFile, []byte(final), 0644)
 		if err != nil {
-			fmt.Println("❌ Failed to save updated YAML:", err)
-			return
+			return fmt.Errorf("failed to save updated YAML: %w", err)
 		}
 
 		fmt.Println("✅ YAML updated successfully:", modifyFile)
 		fmt.Println("👉 If you want to apply it, run: kube-ai execute --file", modifyFile)
+		return nil
 	},
 }
 
@@ -0,0 +1,684 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/yaml"
+)
+
+// netEndpoint, NetworkPolicy'lerin seçtiği bir iş yüküdür: bir Pod veya aynı
+// controller'a ait pod'ların tamamı (örn. deployment/web).
+type netEndpoint struct {
+	Resource   string // kind/name, küçük harf
+	Namespace  string
+	Labels     map[string]string
+	LabelsPath string // label'ların manifest içindeki yolu, bulgularda kullanılır
+	Ports      []corev1.ContainerPort
+	IPs        []string
+	Pods       []string // cluster'dan okunduysa pod adları
+}
+
+// netpolObjects, NetworkPolicy analizinin girdisidir.
+type netpolObjects struct {
+	Endpoints  []netEndpoint
+	Policies   []networkingv1.NetworkPolicy
+	Namespaces map[string]map[string]string // namespace adı → label'ları
+}
+
+// addNamespace, namespace'i Kubernetes'in otomatik eklediği
+// kubernetes.io/metadata.name label'ı ile kaydeder.
+func (in *netpolObjects) addNamespace(name string, nsLabels map[string]string) {
+	if in.Namespaces == nil {
+		in.Namespaces = make(map[string]map[string]string)
+	}
+	merged := make(map[string]string)
+	for k, v := range in.Namespaces[name] {
+		merged[k] = v
+	}
+	for k, v := range nsLabels {
+		merged[k] = v
+	}
+	merged[corev1.LabelMetadataName] = name
+	in.Namespaces[name] = merged
+}
+
+// netpolFromManifests, manifest nesnelerinden pod tanımlarını, NetworkPolicy'leri
+// ve Namespace'leri ayıklar. Namespace'i verilmemiş nesneler namespace'e düşer.
+func netpolFromManifests(objects []map[string]interface{}, namespace string) *netpolObjects {
+	in := &netpolObjects{}
+	for _, obj := range objects {
+		switch obj["kind"] {
+		case "Namespace":
+			var ns corev1.Namespace
+			if decodeInto(obj, &ns) == nil {
+				in.addNamespace(ns.Name, ns.Labels)
+			}
+		case "NetworkPolicy":
+			var policy networkingv1.NetworkPolicy
+			if decodeInto(obj, &policy) == nil {
+				if policy.Namespace == "" {
+					policy.Namespace = namespace
+				}
+				in.addNamespace(policy.Namespace, nil)
+				in.Policies = append(in.Policies, policy)
+			}
+		}
+	}
+	for _, t := range podTemplates(objects) {
+		ep := netEndpoint{Resource: t.Resource, Namespace: t.Namespace, Labels: t.Labels, LabelsPath: t.MetaPath + ".labels"}
+		if ep.Namespace == "" {
+			ep.Namespace = namespace
+		}
+		if t.Spec.HostNetwork {
+			// Node ağını kullanan pod'lara NetworkPolicy uygulanmaz
+			continue
+		}
+		for _, c := range t.Spec.Containers {
+			ep.Ports = append(ep.Ports, c.Ports...)
+		}
+		in.addNamespace(ep.Namespace, nil)
+		in.Endpoints = append(in.Endpoints, ep)
+	}
+	return in
+}
+
+// LoadNetpol, namespace'teki çalışan Pod'ları ve NetworkPolicy'leri okur. Aynı
+// controller'a ait pod'lar tek bir iş yükü olarak birleştirilir.
+func (c *Cluster) LoadNetpol(ctx context.Context, namespace string) (*netpolObjects, error) {
+	namespace = c.ResolveNamespace(namespace)
+	pods, err := c.Clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, clusterErrorf("failed to list pods: %w", err)
+	}
+	policies, err := c.Clientset.NetworkingV1().NetworkPolicies(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, clusterErrorf("failed to list networkpolicies: %w", err)
+	}
+
+	in := &netpolObjects{Policies: policies.Items}
+	// Namespace'i okuma yetkisi yoksa yalnızca otomatik label ile devam edilir
+	var nsLabels map[string]string
+	if ns, err := c.Clientset.CoreV1().Namespaces().Get(ctx, namespace, metav1.GetOptions{}); err == nil {
+		nsLabels = ns.Labels
+	}
+	in.addNamespace(namespace, nsLabels)
+
+	index := make(map[string]int)
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.Spec.HostNetwork || pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		resource := podWorkload(pod)
+		n, ok := index[resource]
+		if !ok {
+			n = len(in.Endpoints)
+			index[resource] = n
+			ep := netEndpoint{Resource: resource, Namespace: namespace, Labels: pod.Labels, LabelsPath: "metadata.labels"}
+			for _, c := range pod.Spec.Containers {
+				ep.Ports = append(ep.Ports, c.Ports...)
+			}
+			in.Endpoints = append(in.Endpoints, ep)
+		}
+		in.Endpoints[n].Pods = append(in.Endpoints[n].Pods, pod.Name)
+		for _, ip := range pod.Status.PodIPs {
+			in.Endpoints[n].IPs = append(in.Endpoints[n].IPs, ip.IP)
+		}
+	}
+	return in, nil
+}
+
+// podWorkload, pod'u sahibi olan controller ile adlandırır. Deployment'ın
+// ReplicaSet'i pod-template-hash son eki atılarak deployment'a çevrilir.
+func podWorkload(pod *corev1.Pod) string {
+	owner := metav1.GetControllerOf(pod)
+	if owner == nil {
+		return "pod/" + pod.Name
+	}
+	if hash := pod.Labels["pod-template-hash"]; owner.Kind == "ReplicaSet" && hash != "" && strings.HasSuffix(owner.Name, "-"+hash) {
+		return "deployment/" + strings.TrimSuffix(owner.Name, "-"+hash)
+	}
+	return strings.ToLower(owner.Kind) + "/" + owner.Name
+}
+
+// portRange, bir protokolde From-To (dahil) port aralığıdır.
+type portRange struct {
+	Protocol string
+	From, To int32
+}
+
+// portSet, iki iş yükü arasında izin verilen portlardır. all, tüm protokollerde
+// tüm portları ifade eder.
+type portSet struct {
+	all    bool
+	ranges []portRange
+}
+
+func (s portSet) empty() bool { return !s.all && len(s.ranges) == 0 }
+
+// union, iki kümenin birleşimidir.
+func (s portSet) union(o portSet) portSet {
+	if s.all || o.all {
+		return portSet{all: true}
+	}
+	return portSet{ranges: normalizeRanges(append(slices.Clone(s.ranges), o.ranges...))}
+}
+
+// intersect, iki kümenin kesişimidir.
+func (s portSet) intersect(o portSet) portSet {
+	if s.all {
+		return o
+	}
+	if o.all {
+		return s
+	}
+	var ranges []portRange
+	for _, a := range s.ranges {
+		for _, b := range o.ranges {
+			if a.Protocol == b.Protocol && a.From <= b.To && b.From <= a.To {
+				ranges = append(ranges, portRange{a.Protocol, max(a.From, b.From), min(a.To, b.To)})
+			}
+		}
+	}
+	return portSet{ranges: normalizeRanges(ranges)}
+}
+
+// contains, kümenin protokoldeki portu içerip içermediğini söyler.
+func (s portSet) contains(protocol string, port int32) bool {
+	return s.all || slices.ContainsFunc(s.ranges, func(r portRange) bool {
+		return r.Protocol == protocol && r.From <= port && port <= r.To
+	})
+}
+
+// String, kümeyi "all", "-" veya "80/TCP,8000-8080/TCP" biçiminde yazar.
+func (s portSet) String() string {
+	if s.all {
+		return "all"
+	}
+	if len(s.ranges) == 0 {
+		return "-"
+	}
+	var parts []string
+	for _, r := range s.ranges {
+		switch {
+		case r.From == 1 && r.To == 65535:
+			parts = append(parts, "all/"+r.Protocol)
+		case r.From == r.To:
+			parts = append(parts, fmt.Sprintf("%d/%s", r.From, r.Protocol))
+		default:
+			parts = append(parts, fmt.Sprintf("%d-%d/%s", r.From, r.To, r.Protocol))
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+// normalizeRanges, aralıkları sıralar ve çakışan veya bitişik olanları birleştirir.
+func normalizeRanges(ranges []portRange) []portRange {
+	sort.Slice(ranges, func(i, j int) bool {
+		if ranges[i].Protocol != ranges[j].Protocol {
+			return ranges[i].Protocol < ranges[j].Protocol
+		}
+		return ranges[i].From < ranges[j].From
+	})
+	var out []portRange
+	for _, r := range ranges {
+		if n := len(out); n > 0 && out[n-1].Protocol == r.Protocol && r.From <= out[n-1].To+1 {
+			out[n-1].To = max(out[n-1].To, r.To)
+			continue
+		}
+		out = append(out, r)
+	}
+	return out
+}
+
+// protocolOf, boşsa varsayılan TCP olmak üzere protokolü döndürür.
+func protocolOf(protocol *corev1.Protocol) string {
+	if protocol == nil || *protocol == "" {
+		return string(corev1.ProtocolTCP)
+	}
+	return string(*protocol)
+}
+
+// namedPort, adlandırılmış portu hedef iş yükünün container portlarında çözer.
+func namedPort(dst netEndpoint, name, protocol string) (int32, bool) {
+	for _, p := range dst.Ports {
+		if p.Name == name && protocolOf(&p.Protocol) == protocol {
+			return p.ContainerPort, true
+		}
+	}
+	return 0, false
+}
+
+// rulePorts, bir ingress/egress kuralının hedefe izin verdiği portlardır. Port
+// listesi boşsa tüm portlara izin verilir; adlandırılmış portlar hedefin
+// container portlarında çözülür, çözülemeyenler hiçbir porta izin vermez.
+func rulePorts(ports []networkingv1.NetworkPolicyPort, dst netEndpoint) portSet {
+	if len(ports) == 0 {
+		return portSet{all: true}
+	}
+	var ranges []portRange
+	for _, p := range ports {
+		protocol := protocolOf(p.Protocol)
+		switch {
+		case p.Port == nil:
+			ranges = append(ranges, portRange{protocol, 1, 65535})
+		case p.Port.StrVal != "":
+			if port, ok := namedPort(dst, p.Port.StrVal, protocol); ok {
+				ranges = append(ranges, portRange{protocol, port, port})
+			}
+		default:
+			r := portRange{protocol, p.Port.IntVal, p.Port.IntVal}
+			if p.EndPort != nil && *p.EndPort > r.From {
+				r.To = *p.EndPort
+			}
+			ranges = append(ranges, r)
+		}
+	}
+	return portSet{ranges: normalizeRanges(ranges)}
+}
+
+// selectorMatches, label selector'ın label'larla eşleşip eşleşmediğini söyler.
+// Boş selector her şeyle eşleşir, geçersiz selector hiçbir şeyle.
+func selectorMatches(selector *metav1.LabelSelector, set map[string]string) bool {
+	s, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		return false
+	}
+	return s.Matches(labels.Set(set))
+}
+
+// policyTypes, politikanın ingress ve/veya egress için geçerli olup olmadığını
+// söyler. policyTypes verilmemişse Ingress ve egress kuralı varsa Egress geçerlidir.
+func policyTypes(p *networkingv1.NetworkPolicy) (ingress, egress bool) {
+	if len(p.Spec.PolicyTypes) == 0 {
+		return true, len(p.Spec.Egress) > 0
+	}
+	return slices.Contains(p.Spec.PolicyTypes, networkingv1.PolicyTypeIngress),
+		slices.Contains(p.Spec.PolicyTypes, networkingv1.PolicyTypeEgress)
+}
+
+// netpolAnalysis, politikaların iş yüklerine uygulanmış halidir.
+type netpolAnalysis struct {
+	in      *netpolObjects
+	ingress [][]*networkingv1.NetworkPolicy // iş yükü başına ingress'i kısıtlayan politikalar
+	egress  [][]*networkingv1.NetworkPolicy
+}
+
+// analyzeNetpol, her iş yükünü seçen ingress ve egress politikalarını bulur.
+func analyzeNetpol(in *netpolObjects) *netpolAnalysis {
+	a := &netpolAnalysis{
+		in:      in,
+		ingress: make([][]*networkingv1.NetworkPolicy, len(in.Endpoints)),
+		egress:  make([][]*networkingv1.NetworkPolicy, len(in.Endpoints)),
+	}
+	for i, ep := range in.Endpoints {
+		for j := range in.Policies {
+			p := &in.Policies[j]
+			if p.Namespace != ep.Namespace || !selectorMatches(&p.Spec.PodSelector, ep.Labels) {
+				continue
+			}
+			ingress, egress := policyTypes(p)
+			if ingress {
+				a.ingress[i] = append(a.ingress[i], p)
+			}
+			if egress {
+				a.egress[i] = append(a.egress[i], p)
+			}
+		}
+	}
+	return a
+}
+
+// peerMatches, politikadaki bir peer'in iş yükünü kapsayıp kapsamadığını söyler.
+func (a *netpolAnalysis) peerMatches(peer networkingv1.NetworkPolicyPeer, policyNamespace string, ep netEndpoint) bool {
+	if peer.IPBlock != nil {
+		return ipBlockMatches(peer.IPBlock, ep.IPs)
+	}
+	if peer.PodSelector == nil && peer.NamespaceSelector == nil {
+		return false
+	}
+	if peer.NamespaceSelector == nil {
+		if ep.Namespace != policyNamespace {
+			return false
+		}
+	} else if !selectorMatches(peer.NamespaceSelector, a.in.Namespaces[ep.Namespace]) {
+		return false
+	}
+	return peer.PodSelector == nil || selectorMatches(peer.PodSelector, ep.Labels)
+}
+
+// ipBlockMatches, iş yükünün IP'lerinden birinin CIDR içinde ve except dışında olup olmadığını söyler.
+func ipBlockMatches(block *networkingv1.IPBlock, ips []string) bool {
+	_, cidr, err := net.ParseCIDR(block.CIDR)
+	if err != nil {
+		return false
+	}
+	for _, s := range ips {
+		ip := net.ParseIP(s)
+		if ip == nil || !cidr.Contains(ip) {
+			continue
+		}
+		excluded := slices.ContainsFunc(block.Except, func(except string) bool {
+			_, n, err := net.ParseCIDR(except)
+			return err == nil && n.Contains(ip)
+		})
+		if !excluded {
+			return true
+		}
+	}
+	return false
+}
+
+// allowedIngress, dst'nin ingress politikalarının src'den kabul ettiği portlardır.
+func (a *netpolAnalysis) allowedIngress(src, dst int) portSet {
+	if len(a.ingress[dst]) == 0 {
+		return portSet{all: true}
+	}
+	var allowed portSet
+	for _, p := range a.ingress[dst] {
+		for _, rule := range p.Spec.Ingress {
+			if len(rule.From) == 0 || slices.ContainsFunc(rule.From, func(peer networkingv1.NetworkPolicyPeer) bool {
+				return a.peerMatches(peer, p.Namespace, a.in.Endpoints[src])
+			}) {
+				allowed = allowed.union(rulePorts(rule.Ports, a.in.Endpoints[dst]))
+			}
+		}
+	}
+	return allowed
+}
+
+// allowedEgress, src'nin egress politikalarının dst'ye izin verdiği portlardır.
+func (a *netpolAnalysis) allowedEgress(src, dst int) portSet {
+	if len(a.egress[src]) == 0 {
+		return portSet{all: true}
+	}
+	var allowed portSet
+	for _, p := range a.egress[src] {
+		for _, rule := range p.Spec.Egress {
+			if len(rule.To) == 0 || slices.ContainsFunc(rule.To, func(peer networkingv1.NetworkPolicyPeer) bool {
+				return a.peerMatches(peer, p.Namespace, a.in.Endpoints[dst])
+			}) {
+				allowed = allowed.union(rulePorts(rule.Ports, a.in.Endpoints[dst]))
+			}
+		}
+	}
+	return allowed
+}
+
+// reach, src'den dst'ye izin verilen portlardır: dst'nin ingress'i ile src'nin egress'inin kesişimi.
+func (a *netpolAnalysis) reach(src, dst int) portSet {
+	return a.allowedIngress(src, dst).intersect(a.allowedEgress(src, dst))
+}
+
+// findEndpoint, iş yükünü kind/name, yalnızca ad veya (cluster'da) pod adıyla bulur.
+func (a *netpolAnalysis) findEndpoint(name string) (int, error) {
+	var matches []int
+	for i, ep := range a.in.Endpoints {
+		_, short, _ := strings.Cut(ep.Resource, "/")
+		if strings.EqualFold(ep.Resource, name) || ep.Namespace+"/"+ep.Resource == name {
+			return i, nil
+		}
+		if short == name || slices.Contains(ep.Pods, strings.TrimPrefix(name, "pod/")) {
+			matches = append(matches, i)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return -1, usageErrorf("no pod or workload named %q found", name)
+	case 1:
+		return matches[0], nil
+	}
+	var names []string
+	for _, i := range matches {
+		names = append(names, a.label(i))
+	}
+	return -1, usageErrorf("%q is ambiguous: %s", name, strings.Join(names, ", "))
+}
+
+// label, iş yükünü tablolarda gösterilecek biçimde adlandırır; birden fazla
+// namespace varsa namespace öne eklenir.
+func (a *netpolAnalysis) label(i int) string {
+	ep := a.in.Endpoints[i]
+	if len(a.in.Namespaces) > 1 {
+		return ep.Namespace + "/" + ep.Resource
+	}
+	return ep.Resource
+}
+
+// policyNames, politikaları virgülle ayrılmış adlarıyla döndürür.
+func policyNames(policies []*networkingv1.NetworkPolicy) string {
+	var names []string
+	for _, p := range policies {
+		names = append(names, p.Name)
+	}
+	return strings.Join(names, ",")
+}
+
+// writeIsolation, her iş yükünün ingress/egress yalıtımını tablo olarak yazar.
+func (a *netpolAnalysis) writeIsolation(out io.Writer) {
+	if len(a.in.Endpoints) == 0 {
+		fmt.Fprintln(out, "\n🛡️ No pods found.")
+		return
+	}
+	isolation := func(policies []*networkingv1.NetworkPolicy) string {
+		if len(policies) == 0 {
+			return "open"
+		}
+		return "isolated"
+	}
+	fmt.Fprintln(out, "\n🛡️ Network isolation:")
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "NAMESPACE\tWORKLOAD\tINGRESS\tEGRESS\tPOLICIES")
+	for i, ep := range a.in.Endpoints {
+		policies := slices.Clone(a.ingress[i])
+		for _, p := range a.egress[i] {
+			if !slices.Contains(policies, p) {
+				policies = append(policies, p)
+			}
+		}
+		names := policyNames(policies)
+		if names == "" {
+			names = "-"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", ep.Namespace, ep.Resource, isolation(a.ingress[i]), isolation(a.egress[i]), names)
+	}
+	w.Flush()
+}
+
+// writeMatrix, iş yükleri arasında izin verilen portları matris olarak yazar.
+// Satır kaynak, sütun hedeftir.
+func (a *netpolAnalysis) writeMatrix(out io.Writer) {
+	if len(a.in.Endpoints) == 0 {
+		return
+	}
+	fmt.Fprintln(out, "\n🧭 Coverage matrix (allowed ports, row → column):")
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	header := []string{"FROM \\ TO"}
+	for i := range a.in.Endpoints {
+		header = append(header, fmt.Sprintf("[%d]", i+1))
+	}
+	fmt.Fprintln(w, strings.Join(header, "\t"))
+	for src := range a.in.Endpoints {
+		row := []string{fmt.Sprintf("[%d] %s", src+1, a.label(src))}
+		for dst := range a.in.Endpoints {
+			row = append(row, a.reach(src, dst).String())
+		}
+		fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+	w.Flush()
+}
+
+// parseNetPort, --port değerini (8080, 8080/UDP veya adlandırılmış port) hedefte
+// çözer ve protokol ile port numarasını döndürür.
+func parseNetPort(value string, dst netEndpoint) (string, int32, error) {
+	name, protocol, _ := strings.Cut(value, "/")
+	protocol = strings.ToUpper(protocol)
+	if protocol == "" {
+		protocol = string(corev1.ProtocolTCP)
+	}
+	if !slices.Contains([]string{"TCP", "UDP", "SCTP"}, protocol) {
+		return "", 0, usageErrorf("invalid --port protocol %q (valid: TCP, UDP, SCTP)", protocol)
+	}
+	if n, err := strconv.Atoi(name); err == nil {
+		if n < 1 || n > 65535 {
+			return "", 0, usageErrorf("invalid --port %q: must be between 1 and 65535", value)
+		}
+		return protocol, int32(n), nil
+	}
+	port, ok := namedPort(dst, name, protocol)
+	if !ok {
+		return "", 0, usageErrorf("%s has no %s port named %q", dst.Resource, protocol, name)
+	}
+	return protocol, port, nil
+}
+
+// answerReach, src'nin dst'ye (verilmişse port üzerinden) erişip erişemeyeceğini
+// ve trafiği engelleyen politikaları yazar.
+func (a *netpolAnalysis) answerReach(out io.Writer, src, dst int, port string) error {
+	ingress, egress := a.allowedIngress(src, dst), a.allowedEgress(src, dst)
+	allowed := ingress.intersect(egress)
+	from, to := a.label(src), a.label(dst)
+
+	if port == "" {
+		if allowed.empty() {
+			fmt.Fprintf(out, "\n❌ %s cannot reach %s on any port.\n", from, to)
+		} else {
+			fmt.Fprintf(out, "\n✅ %s can reach %s on: %s\n", from, to, allowed)
+		}
+	} else {
+		protocol, number, err := parseNetPort(port, a.in.Endpoints[dst])
+		if err != nil {
+			return err
+		}
+		target := fmt.Sprintf("%d/%s", number, protocol)
+		if allowed.contains(protocol, number) {
+			fmt.Fprintf(out, "\n✅ %s can reach %s on port %s.\n", from, to, target)
+			return nil
+		}
+		fmt.Fprintf(out, "\n❌ %s cannot reach %s on port %s.\n", from, to, target)
+		ingress = portSet{ranges: []portRange{{protocol, number, number}}}.intersect(ingress)
+		egress = portSet{ranges: []portRange{{protocol, number, number}}}.intersect(egress)
+	}
+	if ingress.empty() {
+		fmt.Fprintf(out, "   Blocked by ingress policies of %s: %s\n", to, policyNames(a.ingress[dst]))
+	}
+	if egress.empty() {
+		fmt.Fprintf(out, "   Blocked by egress policies of %s: %s\n", from, policyNames(a.egress[src]))
+	}
+	return nil
+}
+
+// netpolCheckIDs, NetworkPolicy analizinin ürettiği bulguların ruleId'leridir.
+var netpolCheckIDs = []string{"netpol-no-policies", "netpol-no-ingress-isolation", "netpol-no-egress-isolation", "netpol-unused-policy"}
+
+// isNetpolCheck, ruleId'nin bir NetworkPolicy kontrolüne ait olup olmadığını söyler.
+func isNetpolCheck(id string) bool {
+	return slices.Contains(netpolCheckIDs, id)
+}
+
+// netpolFindings, yalıtımsız iş yüklerini ve hiçbir pod seçmeyen politikaları
+// bulgu olarak döndürür. Hiç politikası olmayan namespace tek bulgu olarak
+// bildirilir, iş yükleri ayrıca listelenmez.
+func (a *netpolAnalysis) netpolFindings() []Finding {
+	var findings []Finding
+	hasPolicies := make(map[string]bool)
+	for _, p := range a.in.Policies {
+		hasPolicies[p.Namespace] = true
+	}
+
+	reported := make(map[string]bool)
+	for i, ep := range a.in.Endpoints {
+		if !hasPolicies[ep.Namespace] {
+			if !reported[ep.Namespace] {
+				reported[ep.Namespace] = true
+				findings = append(findings, Finding{
+					RuleID:      "netpol-no-policies",
+					Severity:    "high",
+					Resource:    "namespace/" + ep.Namespace,
+					Namespace:   ep.Namespace,
+					Description: fmt.Sprintf("Namespace %s has pods but no NetworkPolicies; every pod accepts and sends traffic to anything.", ep.Namespace),
+					Remediation: "Add a default-deny NetworkPolicy (empty podSelector, policyTypes Ingress and Egress) and allow only the traffic each workload needs.",
+					Source:      SourceRule,
+				})
+			}
+			continue
+		}
+		if len(a.ingress[i]) == 0 {
+			findings = append(findings, Finding{
+				RuleID:      "netpol-no-ingress-isolation",
+				Severity:    "medium",
+				Resource:    ep.Resource,
+				FieldPath:   ep.LabelsPath,
+				Namespace:   ep.Namespace,
+				Description: fmt.Sprintf("No NetworkPolicy selects %s for Ingress; it accepts traffic from every pod in the cluster.", ep.Resource),
+				Remediation: "Select the workload with an Ingress NetworkPolicy that allows only its clients and ports.",
+				Source:      SourceRule,
+			})
+		}
+		if len(a.egress[i]) == 0 {
+			findings = append(findings, Finding{
+				RuleID:      "netpol-no-egress-isolation",
+				Severity:    "low",
+				Resource:    ep.Resource,
+				FieldPath:   ep.LabelsPath,
+				Namespace:   ep.Namespace,
+				Description: fmt.Sprintf("No NetworkPolicy selects %s for Egress; it can connect anywhere, including outside the cluster.", ep.Resource),
+				Remediation: "Select the workload with an Egress NetworkPolicy that allows DNS and the destinations it needs.",
+				Source:      SourceRule,
+			})
+		}
+	}
+
+	for _, p := range a.in.Policies {
+		used := slices.ContainsFunc(a.in.Endpoints, func(ep netEndpoint) bool {
+			return ep.Namespace == p.Namespace && selectorMatches(&p.Spec.PodSelector, ep.Labels)
+		})
+		if !used {
+			findings = append(findings, Finding{
+				RuleID:      "netpol-unused-policy",
+				Severity:    "info",
+				Resource:    "networkpolicy/" + p.Name,
+				FieldPath:   "spec.podSelector",
+				Namespace:   p.Namespace,
+				Description: fmt.Sprintf("NetworkPolicy %s/%s selects no pods.", p.Namespace, p.Name),
+				Remediation: "Fix the podSelector labels or delete the policy.",
+				Source:      SourceRule,
+			})
+		}
+	}
+	sortFindings(findings)
+	return findings
+}
+
+// netpolText, analizi (yalıtım tablosu, matris, politikalar) AI'a gönderilecek
+// metin olarak döndürür.
+func (a *netpolAnalysis) netpolText(answer string) string {
+	var buf bytes.Buffer
+	a.writeIsolation(&buf)
+	a.writeMatrix(&buf)
+	buf.WriteString(answer)
+	if len(a.in.Policies) > 0 {
+		buf.WriteString("\nNetworkPolicies:\n")
+		for _, p := range a.in.Policies {
+			spec, err := yaml.Marshal(p.Spec)
+			if err != nil {
+				continue
+			}
+			fmt.Fprintf(&buf, "--- %s/%s\n%s", p.Namespace, p.Name, spec)
+		}
+	}
+	return strings.TrimSpace(buf.String())
+}
@@ -0,0 +1,324 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestPortSet(t *testing.T) {
+	tcp := func(from, to int32) portRange { return portRange{"TCP", from, to} }
+	tests := []struct {
+		name string
+		got  portSet
+		want string
+	}{
+		{"union joins adjacent ranges", portSet{ranges: []portRange{tcp(80, 80)}}.union(portSet{ranges: []portRange{tcp(81, 81)}}), "80-81/TCP"},
+		{"union with all", portSet{ranges: []portRange{tcp(80, 80)}}.union(portSet{all: true}), "all"},
+		{"union of empty sets", portSet{}.union(portSet{}), "-"},
+		{"intersect overlapping ranges", portSet{ranges: []portRange{tcp(8000, 8080)}}.intersect(portSet{ranges: []portRange{tcp(8080, 9000), {"UDP", 53, 53}}}), "8080/TCP"},
+		{"intersect with all", portSet{all: true}.intersect(portSet{ranges: []portRange{{"UDP", 53, 53}}}), "53/UDP"},
+		{"intersect other protocol", portSet{ranges: []portRange{tcp(53, 53)}}.intersect(portSet{ranges: []portRange{{"UDP", 53, 53}}}), "-"},
+		{"intersect every port", portSet{ranges: []portRange{tcp(1, 65535)}}.intersect(portSet{ranges: []portRange{tcp(443, 443), tcp(8443, 8443)}}), "443/TCP,8443/TCP"},
+		{"every port of a protocol", portSet{ranges: []portRange{tcp(1, 65535)}}, "all/TCP"},
+		{"normalize merges and sorts", portSet{ranges: normalizeRanges([]portRange{tcp(90, 100), {"UDP", 53, 53}, tcp(80, 89), tcp(95, 120)})}, "80-120/TCP,53/UDP"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.got.String(); got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+
+	set := portSet{ranges: []portRange{tcp(8000, 8080)}}
+	if !set.contains("TCP", 8000) || !set.contains("TCP", 8080) || set.contains("TCP", 8081) || set.contains("UDP", 8000) {
+		t.Errorf("contains does not respect the range bounds and protocol of %s", set)
+	}
+}
+
+// shopManifest, iki namespace'te varsayılan-red politikası ve izinleri olan iş yükleridir.
+const shopManifest = `apiVersion: apps/v1
+kind: Deployment
+metadata: {name: web}
+spec:
+  template:
+    metadata:
+      labels: {app: web}
+    spec:
+      containers:
+      - name: web
+        image: web:1
+        ports: [{name: http, containerPort: 8080}]
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata: {name: api}
+spec:
+  template:
+    metadata:
+      labels: {app: api}
+    spec:
+      containers:
+      - name: api
+        image: api:1
+        ports:
+        - {name: grpc, containerPort: 9090}
+        - {name: metrics, containerPort: 9100}
+---
+apiVersion: apps/v1
+kind: StatefulSet
+metadata: {name: db}
+spec:
+  template:
+    metadata:
+      labels: {app: db}
+    spec:
+      containers:
+      - name: db
+        image: postgres:16
+        ports: [{containerPort: 5432}]
+---
+apiVersion: v1
+kind: Pod
+metadata:
+  name: prometheus
+  namespace: monitoring
+  labels: {app: prometheus}
+spec:
+  containers:
+  - name: prometheus
+    image: prometheus:2
+---
+apiVersion: networking.k8s.io/v1
+kind: NetworkPolicy
+metadata: {name: default-deny}
+spec:
+  podSelector: {}
+  policyTypes: [Ingress, Egress]
+---
+apiVersion: networking.k8s.io/v1
+kind: NetworkPolicy
+metadata: {name: api-ingress}
+spec:
+  podSelector:
+    matchLabels: {app: api}
+  ingress:
+  - from:
+    - podSelector:
+        matchLabels: {app: web}
+    ports: [{port: grpc}]
+  - from:
+    - namespaceSelector:
+        matchLabels: {kubernetes.io/metadata.name: monitoring}
+    ports: [{port: 9100}]
+---
+apiVersion: networking.k8s.io/v1
+kind: NetworkPolicy
+metadata: {name: web-egress}
+spec:
+  podSelector:
+    matchLabels: {app: web}
+  policyTypes: [Egress]
+  egress:
+  - to:
+    - podSelector:
+        matchLabels: {app: api}
+---
+apiVersion: networking.k8s.io/v1
+kind: NetworkPolicy
+metadata: {name: db-ingress}
+spec:
+  podSelector:
+    matchLabels: {app: db}
+  ingress:
+  - from:
+    - podSelector:
+        matchLabels: {app: api}
+    ports: [{port: 5432}]
+`
+
+// shopAnalysis, shopManifest'i shop namespace'inde analiz eder.
+func shopAnalysis(t *testing.T) *netpolAnalysis {
+	t.Helper()
+	quietStatus(t)
+	return analyzeNetpol(netpolFromManifests(manifestObjects(shopManifest, "shop.yaml"), "shop"))
+}
+
+func TestNetpolReach(t *testing.T) {
+	a := shopAnalysis(t)
+	tests := []struct {
+		src, dst string
+		want     string
+	}{
+		{"deployment/web", "deployment/api", "9090/TCP"},
+		{"deployment/web", "statefulset/db", "-"},
+		// db'nin ingress'i 5432'ye izin verir ama api'nin egress'i yalnızca varsayılan-red
+		{"deployment/api", "statefulset/db", "-"},
+		// namespaceSelector tek başına diğer namespace'in tüm pod'larını seçer
+		{"pod/prometheus", "deployment/api", "9100/TCP"},
+		{"pod/prometheus", "deployment/web", "-"},
+		// podSelector tek başına yalnızca politikanın namespace'indeki pod'ları seçer
+		{"deployment/web", "pod/prometheus", "-"},
+		{"deployment/api", "pod/prometheus", "-"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.src+" to "+tt.dst, func(t *testing.T) {
+			src, err := a.findEndpoint(tt.src)
+			if err != nil {
+				t.Fatal(err)
+			}
+			dst, err := a.findEndpoint(tt.dst)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := a.reach(src, dst).String(); got != tt.want {
+				t.Errorf("reach = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAnswerReach(t *testing.T) {
+	a := shopAnalysis(t)
+	web, _ := a.findEndpoint("web")
+	db, _ := a.findEndpoint("db")
+	api, _ := a.findEndpoint("api")
+
+	var out bytes.Buffer
+	if err := a.answerReach(&out, web, db, "5432"); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"shop/deployment/web cannot reach shop/statefulset/db on port 5432/TCP",
+		"Blocked by ingress policies of shop/statefulset/db: default-deny,db-ingress",
+		"Blocked by egress policies of shop/deployment/web: default-deny,web-egress",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("answer has no %q:\n%s", want, out.String())
+		}
+	}
+
+	out.Reset()
+	if err := a.answerReach(&out, web, api, "grpc"); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "can reach shop/deployment/api on port 9090/TCP") {
+		t.Errorf("named port answer:\n%s", out.String())
+	}
+	if err := a.answerReach(&out, web, api, "admin"); ExitCode(err) != ExitUsage {
+		t.Errorf("unknown named port: err %v", err)
+	}
+}
+
+func TestNetpolFindings(t *testing.T) {
+	tests := []struct {
+		name     string
+		manifest string
+		want     []string
+	}{
+		{
+			name:     "policies select every workload",
+			manifest: shopManifest,
+			want: []string{
+				"netpol-no-policies high namespace/monitoring ",
+			},
+		},
+		{
+			name: "ingress-only policy and an unused policy",
+			manifest: `apiVersion: apps/v1
+kind: Deployment
+metadata: {name: web}
+spec:
+  template:
+    metadata:
+      labels: {app: web}
+    spec:
+      containers: [{name: web, image: web:1}]
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata: {name: worker}
+spec:
+  template:
+    metadata:
+      labels: {app: worker}
+    spec:
+      containers: [{name: worker, image: worker:1}]
+---
+apiVersion: v1
+kind: Pod
+metadata: {name: node-exporter}
+spec:
+  hostNetwork: true
+  containers: [{name: exporter, image: exporter:1}]
+---
+apiVersion: networking.k8s.io/v1
+kind: NetworkPolicy
+metadata: {name: web}
+spec:
+  podSelector:
+    matchLabels: {app: web}
+  ingress: [{}]
+---
+apiVersion: networking.k8s.io/v1
+kind: NetworkPolicy
+metadata: {name: legacy}
+spec:
+  podSelector:
+    matchLabels: {app: gone}
+`,
+			want: []string{
+				"netpol-no-egress-isolation low deployment/web spec.template.metadata.labels",
+				"netpol-no-ingress-isolation medium deployment/worker spec.template.metadata.labels",
+				"netpol-no-egress-isolation low deployment/worker spec.template.metadata.labels",
+				"netpol-unused-policy info networkpolicy/legacy spec.podSelector",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			quietStatus(t)
+			a := analyzeNetpol(netpolFromManifests(manifestObjects(tt.manifest, "test.yaml"), "default"))
+			assertFindings(t, a.netpolFindings(), tt.want)
+		})
+	}
+}
+
+func TestParseNetPort(t *testing.T) {
+	dst := netEndpoint{Resource: "deployment/dns", Ports: []corev1.ContainerPort{
+		{Name: "dns", ContainerPort: 53, Protocol: corev1.ProtocolUDP},
+		{Name: "http", ContainerPort: 8080},
+	}}
+	tests := []struct {
+		value    string
+		protocol string
+		port     int32
+		wantErr  bool
+	}{
+		{"8080", "TCP", 8080, false},
+		{"53/udp", "UDP", 53, false},
+		{"http", "TCP", 8080, false},
+		{"dns/UDP", "UDP", 53, false},
+		{"dns", "", 0, true},
+		{"0", "", 0, true},
+		{"65536", "", 0, true},
+		{"80/ICMP", "", 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			protocol, port, err := parseNetPort(tt.value, dst)
+			if tt.wantErr {
+				if ExitCode(err) != ExitUsage {
+					t.Errorf("err = %v, want a usage error", err)
+				}
+				return
+			}
+			if err != nil || protocol != tt.protocol || port != tt.port {
+				t.Errorf("got %s %d %v, want %s %d", protocol, port, err, tt.protocol, tt.port)
+			}
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// Bu boyutu aşan ConfigMap değerleri kısaltılır.
+	maxConfigMapValue = 2048
+	// Kısaltılan bir değerin korunan baş kısmı.
+	configMapValueHead = 1024
+)
+
+// Modele bir şey anlatmayan ama binlerce token tutabilen metadata alanları.
+var noiseMetadataFields = []string{"managedFields", "resourceVersion", "uid", "selfLink", "generation"}
+
+// Gürültü olarak atılan annotation'lar.
+var noiseAnnotations = []string{lastAppliedAnnotation}
+
+// normalizeObject, bir Kubernetes nesnesinden (veya listesinden) managedFields,
+// resourceVersion, uid ve last-applied-configuration gibi gürültü alanlarını
+// atar ve büyük ConfigMap değerlerini kısaltır. Nesne değiştiyse true döner.
+func normalizeObject(obj map[string]interface{}) bool {
+	changed := false
+	if items, ok := obj["items"].([]interface{}); ok {
+		for _, item := range items {
+			if m, ok := item.(map[string]interface{}); ok && normalizeObject(m) {
+				changed = true
+			}
+		}
+	}
+
+	if meta, ok := obj["metadata"].(map[string]interface{}); ok {
+		for _, field := range noiseMetadataFields {
+			if _, ok := meta[field]; ok {
+				delete(meta, field)
+				changed = true
+			}
+		}
+		if annotations, ok := meta["annotations"].(map[string]interface{}); ok {
+			for _, name := range noiseAnnotations {
+				if _, ok := annotations[name]; ok {
+					delete(annotations, name)
+					changed = true
+				}
+			}
+			if len(annotations) == 0 {
+				delete(meta, "annotations")
+			}
+		}
+		if owners, ok := meta["ownerReferences"].([]interface{}); ok {
+			for _, owner := range owners {
+				if ref, ok := owner.(map[string]interface{}); ok {
+					if _, ok := ref["uid"]; ok {
+						delete(ref, "uid")
+						changed = true
+					}
+				}
+			}
+		}
+	}
+
+	if obj["kind"] == "ConfigMap" && shrinkConfigMap(obj) {
+		changed = true
+	}
+	return changed
+}
+
+// shrinkConfigMap, büyük data değerlerinin yalnızca başını bırakır ve
+// binaryData içeriğini boyutuyla değiştirir.
+func shrinkConfigMap(obj map[string]interface{}) bool {
+	changed := false
+	if data, ok := obj["data"].(map[string]interface{}); ok {
+		for key, value := range data {
+			s, ok := value.(string)
+			if !ok || len(s) <= maxConfigMapValue {
+				continue
+			}
+			// Kesim çok baytlı bir karakterin ortasına denk gelmesin
+			head := configMapValueHead
+			for head > 0 && !utf8.RuneStart(s[head]) {
+				head--
+			}
+			data[key] = fmt.Sprintf("%s\n... <truncated: %d more bytes>", s[:head], len(s)-head)
+			changed = true
+		}
+	}
+	if binary, ok := obj["binaryData"].(map[string]interface{}); ok {
+		for key, value := range binary {
+			if s, ok := value.(string); ok {
+				binary[key] = fmt.Sprintf("<binary: %d base64 bytes>", len(s))
+				changed = true
+			}
+		}
+	}
+	return changed
+}
+
+// normalizeText, dosyadan okunan YAML manifest'lerini normalize eder. Manifest
+// olmayan metin (describe çıktısı, loglar) ve değişmeyen belgeler olduğu gibi kalır.
+func normalizeText(text string) string {
+	docs := yamlDocSeparator.Split(text, -1)
+	if len(docs) == 1 && !strings.Contains(text, "kind:") && !strings.HasPrefix(strings.TrimSpace(text), "{") {
+		return text
+	}
+	for i, doc := range docs {
+		var obj map[string]interface{}
+		if err := yaml.Unmarshal([]byte(doc), &obj); err != nil || (obj["kind"] == nil && obj["apiVersion"] == nil) {
+			continue
+		}
+		if normalizeObject(obj) {
+			if out, err := yaml.Marshal(obj); err == nil {
+				docs[i] = string(out)
+			}
+		}
+	}
+	return strings.Join(docs, "---\n")
+}
+
+// prepareInput, bir girdi dosyasının içeriğini modele gönderilmeye hazırlar:
+// --raw verilmediyse gürültü alanlarını atar, ardından gizli değerleri maskeler.
+func prepareInput(text, location string) string {
+	if !Raw {
+		text = normalizeText(text)
+	}
+	return redactor().Text(text, location)
+}
@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"sigs.k8s.io/yaml"
+)
+
+func TestNormalizeText(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{
+			name: "noise metadata is dropped",
+			text: `apiVersion: v1
+kind: Service
+metadata:
+  name: web
+  uid: 1b2c
+  resourceVersion: "42"
+  managedFields: [{manager: kubectl}]
+  annotations:
+    kubectl.kubernetes.io/last-applied-configuration: '{}'
+  ownerReferences: [{kind: Deployment, name: web, uid: 9f8e}]
+spec: {ports: [{port: 80}]}
+`,
+			want: `apiVersion: v1
+kind: Service
+metadata:
+  name: web
+  ownerReferences:
+  - kind: Deployment
+    name: web
+spec:
+  ports:
+  - port: 80
+`,
+		},
+		{
+			name: "clean manifests keep their formatting",
+			text: "kind: ConfigMap   # settings\nmetadata: {name: settings}\ndata: {mode: fast}\n",
+			want: "kind: ConfigMap   # settings\nmetadata: {name: settings}\ndata: {mode: fast}\n",
+		},
+		{
+			name: "logs are not touched",
+			text: "uid: 1b2c is not a manifest field here\n",
+			want: "uid: 1b2c is not a manifest field here\n",
+		},
+		{
+			name: "binaryData is replaced by its size",
+			text: "kind: ConfigMap\nmetadata: {name: certs}\nbinaryData: {ca.der: AAECAwQ=}\n",
+			want: "binaryData:\n  ca.der: '<binary: 8 base64 bytes>'\nkind: ConfigMap\nmetadata:\n  name: certs\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := normalizeText(tt.text); got != tt.want {
+				t.Errorf("normalizeText:\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestShrinkConfigMap(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		wantHead int // korunan bayt sayısı; -1 değerin değişmediğini gösterir
+	}{
+		{"small value is kept", strings.Repeat("a", maxConfigMapValue), -1},
+		{"ASCII is cut at the head size", strings.Repeat("a", maxConfigMapValue+1), configMapValueHead},
+		// "ş" iki bayttır; tek bir ASCII bayt kaydırınca 1024. bayt bir karakterin ortasına düşer
+		{"multi-byte text is cut on a rune boundary", "a" + strings.Repeat("ş", maxConfigMapValue), configMapValueHead - 1},
+		{"four-byte runes", strings.Repeat("🚀", maxConfigMapValue), configMapValueHead},
+		{"four-byte runes shifted", "ab" + strings.Repeat("🚀", maxConfigMapValue), configMapValueHead - 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			obj := map[string]interface{}{"kind": "ConfigMap", "data": map[string]interface{}{"config": tt.value}}
+			changed := shrinkConfigMap(obj)
+			got := obj["data"].(map[string]interface{})["config"].(string)
+			if tt.wantHead < 0 {
+				if changed || got != tt.value {
+					t.Errorf("value changed (changed=%v)", changed)
+				}
+				return
+			}
+			head, _, _ := strings.Cut(got, "\n... <truncated:")
+			if len(head) != tt.wantHead || head != tt.value[:tt.wantHead] {
+				t.Errorf("kept %d bytes, want %d", len(head), tt.wantHead)
+			}
+			if !utf8.ValidString(got) {
+				t.Error("truncated value is not valid UTF-8")
+			}
+			if _, err := yaml.Marshal(obj); err != nil {
+				t.Errorf("marshal: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,276 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/ext"
+	yaml "go.yaml.in/yaml/v3"
+)
+
+// SourcePolicy, kurum politikalarından gelen bulguların kaynağıdır.
+const SourcePolicy = "policy"
+
+// OrgPolicy, policy dizinindeki bir CEL politikasıdır. expression, nesne
+// politikaya uyuyorsa true döner. forEach: containers ile ifade her container
+// (init container'lar dahil) için ayrı değerlendirilir.
+type OrgPolicy struct {
+	ID                string   `yaml:"id"`
+	Severity          string   `yaml:"severity"`
+	Description       string   `yaml:"description"`
+	Kinds             []string `yaml:"kinds"`
+	ForEach           string   `yaml:"forEach"`
+	Expression        string   `yaml:"expression"`
+	Message           string   `yaml:"message"`
+	MessageExpression string   `yaml:"messageExpression"`
+	FieldPath         string   `yaml:"fieldPath"`
+	Remediation       string   `yaml:"remediation"`
+
+	file    string
+	program cel.Program
+	message cel.Program
+}
+
+// orgPolicies, audit'in yüklediği politikalardır; mergeFindings AI'ın bunlar
+// için yazdığı açıklamaları ayırt etmek için kullanır.
+var orgPolicies []*OrgPolicy
+
+// isOrgPolicy, ruleId'nin yüklü bir kurum politikasına ait olup olmadığını söyler.
+func isOrgPolicy(id string) bool {
+	return slices.ContainsFunc(orgPolicies, func(p *OrgPolicy) bool { return p.ID == id })
+}
+
+// policyDir, --policy-dir verilmediğinde kullanılan dizindir: KUBE_AI_POLICIES
+// veya ~/.config/kube-ai/policies.
+func policyDir() string {
+	if dir := os.Getenv("KUBE_AI_POLICIES"); dir != "" {
+		return dir
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".config", "kube-ai", "policies")
+}
+
+// policyEnv, politika ifadelerinin CEL ortamıdır. object manifest'in kendisi,
+// podSpec pod tanımı (yoksa boş), container forEach'teki container'dır.
+func policyEnv() (*cel.Env, error) {
+	return cel.NewEnv(
+		cel.Variable("object", cel.DynType),
+		cel.Variable("podSpec", cel.DynType),
+		cel.Variable("container", cel.DynType),
+		ext.Strings(),
+	)
+}
+
+// loadPolicies, dizindeki *.yaml ve *.yml dosyalarından politikaları okur ve
+// derler. Dizin yoksa, açıkça verilmediyse (explicit false) politika yüklenmez.
+func loadPolicies(dir string, explicit bool) ([]*OrgPolicy, error) {
+	if dir == "" {
+		return nil, nil
+	}
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) && !explicit {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, usageErrorf("failed to read policy directory %s: %w", dir, err)
+	}
+	env, err := policyEnv()
+	if err != nil {
+		return nil, err
+	}
+
+	var policies []*OrgPolicy
+	for _, entry := range entries {
+		suffix := filepath.Ext(entry.Name())
+		if entry.IsDir() || suffix != ".yaml" && suffix != ".yml" {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, usageErrorf("failed to read policy file %s: %w", path, err)
+		}
+		dec := yaml.NewDecoder(strings.NewReader(string(data)))
+		for {
+			var p OrgPolicy
+			if err := dec.Decode(&p); errors.Is(err, io.EOF) {
+				break
+			} else if err != nil {
+				return nil, usageErrorf("failed to parse policy file %s: %w", path, err)
+			}
+			if p.ID == "" && p.Expression == "" {
+				continue // boş doküman
+			}
+			p.file = path
+			if err := p.compile(env); err != nil {
+				return nil, err
+			}
+			if isBuiltinRule(p.ID) || isPSSControl(p.ID) || slices.ContainsFunc(policies, func(o *OrgPolicy) bool { return o.ID == p.ID }) {
+				return nil, usageErrorf("policy %q in %s: id is already used by another rule or policy", p.ID, path)
+			}
+			policies = append(policies, &p)
+		}
+	}
+	sort.SliceStable(policies, func(i, j int) bool { return policies[i].ID < policies[j].ID })
+	return policies, nil
+}
+
+// compile, politikayı doğrular ve CEL ifadelerini derler.
+func (p *OrgPolicy) compile(env *cel.Env) error {
+	if p.ID == "" {
+		return usageErrorf("policy in %s has no id", p.file)
+	}
+	if p.Expression == "" {
+		return usageErrorf("policy %q in %s has no expression", p.ID, p.file)
+	}
+	if p.Severity == "" {
+		p.Severity = "medium"
+	}
+	p.Severity = strings.ToLower(p.Severity)
+	if severityRank(p.Severity) < 0 {
+		return usageErrorf("policy %q in %s has invalid severity %q (valid: %s)", p.ID, p.file, p.Severity, strings.Join(severities, ", "))
+	}
+	if p.ForEach != "" && p.ForEach != "containers" {
+		return usageErrorf("policy %q in %s has invalid forEach %q (valid: containers)", p.ID, p.file, p.ForEach)
+	}
+
+	var err error
+	if p.program, err = compileCEL(env, p.Expression, cel.BoolType); err != nil {
+		return usageErrorf("policy %q in %s: invalid expression: %w", p.ID, p.file, err)
+	}
+	if p.MessageExpression != "" {
+		if p.message, err = compileCEL(env, p.MessageExpression, cel.StringType); err != nil {
+			return usageErrorf("policy %q in %s: invalid messageExpression: %w", p.ID, p.file, err)
+		}
+	}
+	return nil
+}
+
+// compileCEL, ifadeyi derler ve sonuç tipinin want (veya dyn) olduğunu doğrular.
+func compileCEL(env *cel.Env, expression string, want *cel.Type) (cel.Program, error) {
+	ast, issues := env.Compile(expression)
+	if issues != nil && issues.Err() != nil {
+		return nil, issues.Err()
+	}
+	if out := ast.OutputType(); !out.IsExactType(want) && !out.IsExactType(cel.DynType) {
+		return nil, fmt.Errorf("must evaluate to %s, not %s", want, out)
+	}
+	return env.Program(ast)
+}
+
+// matchesKind, politikanın nesnenin türüne uygulanıp uygulanmadığını söyler.
+func (p *OrgPolicy) matchesKind(kind string) bool {
+	return len(p.Kinds) == 0 || slices.ContainsFunc(p.Kinds, func(k string) bool { return strings.EqualFold(k, kind) })
+}
+
+// policyTarget, bir politikanın tek bir değerlendirmesinin girdisidir.
+type policyTarget struct {
+	vars      map[string]interface{}
+	fieldPath string
+}
+
+// policyTargets, nesne için değerlendirilecek girdileri döndürür: forEach
+// yoksa nesnenin kendisi, forEach: containers ise her container.
+func (p *OrgPolicy) policyTargets(obj map[string]interface{}) []policyTarget {
+	kind, _ := obj["kind"].(string)
+	specPath, hasPod := podSpecPaths[kind]
+	podSpec, _ := lookupPath(obj, specPath).(map[string]interface{})
+	if !hasPod || podSpec == nil {
+		podSpec = map[string]interface{}{}
+	}
+	vars := map[string]interface{}{"object": obj, "podSpec": podSpec, "container": map[string]interface{}{}}
+
+	if p.ForEach == "" {
+		fieldPath := p.FieldPath
+		if rest, ok := strings.CutPrefix(fieldPath, "podSpec"); ok && hasPod {
+			fieldPath = specPath + rest
+		}
+		return []policyTarget{{vars: vars, fieldPath: fieldPath}}
+	}
+
+	var targets []policyTarget
+	for _, key := range []string{"initContainers", "containers"} {
+		list, _ := podSpec[key].([]interface{})
+		for i, c := range list {
+			fieldPath := fmt.Sprintf("%s.%s[%d]", specPath, key, i)
+			if p.FieldPath != "" {
+				fieldPath += "." + p.FieldPath
+			}
+			containerVars := map[string]interface{}{"object": obj, "podSpec": podSpec, "container": c}
+			targets = append(targets, policyTarget{vars: containerVars, fieldPath: fieldPath})
+		}
+	}
+	return targets
+}
+
+// runPolicies, politikaları manifest nesnelerine uygular. Değerlendirilemeyen
+// ifadeler (örn. olmayan bir alana has() olmadan erişim) uyarı olarak yazılır.
+func runPolicies(policies []*OrgPolicy, objects []map[string]interface{}) []Finding {
+	var findings []Finding
+	for _, obj := range objects {
+		kind, _ := obj["kind"].(string)
+		resource := objectResource(obj)
+		namespace, _ := lookupPath(obj, "metadata.namespace").(string)
+		for _, p := range policies {
+			if !p.matchesKind(kind) {
+				continue
+			}
+			for _, target := range p.policyTargets(obj) {
+				out, _, err := p.program.Eval(target.vars)
+				if err != nil {
+					fmt.Fprintf(statusOut, "⚠️ Policy %s could not be evaluated on %s: %v\n", p.ID, resource, err)
+					continue
+				}
+				ok, isBool := out.Value().(bool)
+				if !isBool {
+					fmt.Fprintf(statusOut, "⚠️ Policy %s returned %v instead of true or false on %s\n", p.ID, out.Value(), resource)
+					continue
+				}
+				if ok {
+					continue
+				}
+				findings = append(findings, Finding{
+					RuleID:      p.ID,
+					Severity:    p.Severity,
+					Resource:    resource,
+					FieldPath:   target.fieldPath,
+					Namespace:   namespace,
+					Description: p.violationMessage(target.vars),
+					Remediation: p.Remediation,
+					Source:      SourcePolicy,
+				})
+			}
+		}
+	}
+	sortFindings(findings)
+	return findings
+}
+
+// violationMessage, bulgunun açıklamasıdır: messageExpression, message,
+// description sırasıyla ilk verilen.
+func (p *OrgPolicy) violationMessage(vars map[string]interface{}) string {
+	if p.message != nil {
+		if out, _, err := p.message.Eval(vars); err == nil {
+			if text, ok := out.Value().(string); ok && text != "" {
+				return text
+			}
+		}
+	}
+	if p.Message != "" {
+		return p.Message
+	}
+	if p.Description != "" {
+		return p.Description
+	}
+	return fmt.Sprintf("Violates policy %s.", p.ID)
+}
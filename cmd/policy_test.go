@@ -0,0 +1,166 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writePolicyDir, dosya adı → içerik eşlemesini geçici bir politika dizinine yazar.
+func writePolicyDir(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestLoadPolicies(t *testing.T) {
+	dir := writePolicyDir(t, map[string]string{
+		"labels.yaml": `id: require-team-label
+expression: has(object.metadata.labels) && "team" in object.metadata.labels
+---
+id: no-latest
+severity: HIGH
+forEach: containers
+expression: '!container.image.endsWith(":latest")'
+`,
+		"network.yml": "id: no-host-network\nexpression: '!has(podSpec.hostNetwork) || !podSpec.hostNetwork'\n",
+		"README.md":   "not a policy",
+	})
+	policies, err := loadPolicies(dir, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, p := range policies {
+		got = append(got, p.ID+" "+p.Severity)
+	}
+	if want := "no-host-network medium,no-latest high,require-team-label medium"; strings.Join(got, ",") != want {
+		t.Errorf("policies = %q, want %q", strings.Join(got, ","), want)
+	}
+
+	missing := filepath.Join(t.TempDir(), "missing")
+	if policies, err := loadPolicies(missing, false); err != nil || policies != nil {
+		t.Errorf("missing default directory: %v, %v", policies, err)
+	}
+	if _, err := loadPolicies(missing, true); ExitCode(err) != ExitUsage {
+		t.Errorf("missing --policy-dir: err %v, want a usage error", err)
+	}
+}
+
+func TestLoadPoliciesErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  string
+		wantErr string
+	}{
+		{"no id", "expression: 'true'\nseverity: low\n", "has no id"},
+		{"no expression", "id: empty\n", "has no expression"},
+		{"invalid severity", "id: p\nseverity: urgent\nexpression: 'true'\n", `invalid severity "urgent"`},
+		{"invalid forEach", "id: p\nforEach: volumes\nexpression: 'true'\n", `invalid forEach "volumes"`},
+		{"syntax error", "id: p\nexpression: 'object.kind =='\n", "invalid expression"},
+		{"not a bool", "id: p\nexpression: '1 + 1'\n", "must evaluate to bool"},
+		{"message not a string", "id: p\nexpression: 'true'\nmessageExpression: '42'\n", "invalid messageExpression"},
+		{"built-in rule id", "id: host-network\nexpression: 'true'\n", "already used"},
+		{"PSS control id", "id: pss-privileged\nexpression: 'true'\n", "already used"},
+		{"duplicate id", "id: p\nexpression: 'true'\n---\nid: p\nexpression: 'false'\n", "already used"},
+		{"not yaml", "id: [p\n", "failed to parse"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := writePolicyDir(t, map[string]string{"policy.yaml": tt.policy})
+			_, err := loadPolicies(dir, true)
+			if ExitCode(err) != ExitUsage || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("err = %v, want a usage error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRunPolicies(t *testing.T) {
+	dir := writePolicyDir(t, map[string]string{"policies.yaml": `id: require-team-label
+kinds: [Deployment]
+expression: has(object.metadata.labels) && "team" in object.metadata.labels
+fieldPath: metadata.labels
+message: Workloads need a team label.
+---
+id: no-latest
+severity: high
+forEach: containers
+expression: '!container.image.endsWith(":latest")'
+messageExpression: '"container " + container.name + " uses " + container.image'
+fieldPath: image
+---
+id: no-host-network
+severity: high
+expression: '!has(podSpec.hostNetwork) || !podSpec.hostNetwork'
+fieldPath: podSpec.hostNetwork
+description: Pods must not use the node network.
+---
+id: min-replicas
+kinds: [Pod]
+expression: object.spec.replicas > 1
+`})
+	policies, err := loadPolicies(dir, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	manifest := `apiVersion: apps/v1
+kind: Deployment
+metadata: {name: web, namespace: shop}
+spec:
+  template:
+    spec:
+      hostNetwork: true
+      initContainers:
+      - {name: init, image: "busybox:latest"}
+      containers:
+      - {name: web, image: "nginx:1.27"}
+      - {name: sidecar, image: "envoy:latest"}
+---
+apiVersion: v1
+kind: Pod
+metadata:
+  name: tools
+  labels: {team: platform}
+spec:
+  containers:
+  - {name: tools, image: "tools:1"}
+`
+	status := captureStatus(t)
+	findings := runPolicies(policies, manifestObjects(manifest, "test.yaml"))
+	assertFindings(t, findings, []string{
+		"require-team-label medium deployment/web metadata.labels",
+		"no-latest high deployment/web spec.template.spec.initContainers[0].image",
+		"no-latest high deployment/web spec.template.spec.containers[1].image",
+		"no-host-network high deployment/web spec.template.spec.hostNetwork",
+	})
+
+	descriptions := make(map[string]string)
+	for _, f := range findings {
+		if f.Source != SourcePolicy || f.Namespace != "shop" {
+			t.Errorf("finding %s has source %q and namespace %q", f.RuleID, f.Source, f.Namespace)
+		}
+		descriptions[f.RuleID+" "+f.FieldPath] = f.Description
+	}
+	for key, want := range map[string]string{
+		"require-team-label metadata.labels":                   "Workloads need a team label.",
+		"no-latest spec.template.spec.containers[1].image":     "container sidecar uses envoy:latest",
+		"no-latest spec.template.spec.initContainers[0].image": "container init uses busybox:latest",
+		"no-host-network spec.template.spec.hostNetwork":       "Pods must not use the node network.",
+	} {
+		if descriptions[key] != want {
+			t.Errorf("%s: description %q, want %q", key, descriptions[key], want)
+		}
+	}
+
+	if !strings.Contains(status.String(), "Policy min-replicas could not be evaluated on pod/tools") {
+		t.Errorf("no evaluation warning for min-replicas: %q", status)
+	}
+}
@@ -0,0 +1,139 @@
+package cmd
+
+import (
+	"embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/spf13/cobra"
+)
+
+// Dahili prompt şablonları. Aynı adlı bir dosya ~/.config/kube-ai/prompts/
+// (veya KUBE_AI_PROMPTS) altında varsa onun yerine kullanılır.
+//
+//go:embed prompts/*.tmpl
+var embeddedPrompts embed.FS
+
+// PromptData, şablonlarda kullanılabilen değişkenlerdir:
+// {{.Resource}}, {{.Namespace}}, {{.Question}} ve {{.Data}}.
+type PromptData struct {
+	Resource  string
+	Namespace string
+	Question  string
+	Data      string
+}
+
+// promptsDir, kullanıcı şablonlarının dizinini döndürür.
+func promptsDir() string {
+	if dir := os.Getenv("KUBE_AI_PROMPTS"); dir != "" {
+		return dir
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".config", "kube-ai", "prompts")
+}
+
+// promptSource, şablonun metnini ve nereden geldiğini döndürür.
+func promptSource(name string) (string, string, error) {
+	if dir := promptsDir(); dir != "" {
+		path := filepath.Join(dir, name+".tmpl")
+		if data, err := os.ReadFile(path); err == nil {
+			return string(data), path, nil
+		}
+	}
+	data, err := embeddedPrompts.ReadFile("prompts/" + name + ".tmpl")
+	if err != nil {
+		return "", "", usageErrorf("unknown prompt template %q", name)
+	}
+	return string(data), "built-in", nil
+}
+
+// renderPrompt, adı verilen şablonu değişkenlerle doldurur.
+func renderPrompt(name string, data PromptData) (string, error) {
+	text, source, err := promptSource(name)
+	if err != nil {
+		return "", err
+	}
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", usageErrorf("invalid prompt template %s (%s): %w", name, source, err)
+	}
+	var b strings.Builder
+	if err := tmpl.Execute(&b, data); err != nil {
+		return "", usageErrorf("failed to render prompt template %s (%s): %w", name, source, err)
+	}
+	return strings.TrimSpace(b.String()), nil
+}
+
+// renderPrompts, bir komutun sistem ve kullanıcı şablonlarını birlikte doldurur.
+func renderPrompts(command string, data PromptData) (string, string, error) {
+	system, err := renderPrompt(command+"-system", data)
+	if err != nil {
+		return "", "", err
+	}
+	user, err := renderPrompt(command, data)
+	if err != nil {
+		return "", "", err
+	}
+	return system, user, nil
+}
+
+// promptNames, dahili şablonların adlarını sıralı döndürür.
+func promptNames() []string {
+	entries, _ := embeddedPrompts.ReadDir("prompts")
+	var names []string
+	for _, entry := range entries {
+		names = append(names, strings.TrimSuffix(entry.Name(), ".tmpl"))
+	}
+	sort.Strings(names)
+	return names
+}
+
+var PromptsCmd = &cobra.Command{
+	Use:   "prompts",
+	Short: "List and show the prompt templates",
+	Long: `List and show the prompt templates used by each command.
+
+To override a template, copy it to ~/.config/kube-ai/prompts/<name>.tmpl (or $KUBE_AI_PROMPTS) and edit it.
+Templates use Go text/template syntax with the variables {{.Resource}}, {{.Namespace}}, {{.Question}} and {{.Data}}.`,
+}
+
+var promptsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List prompt templates and where each one is loaded from",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		for _, name := range promptNames() {
+			_, source, err := promptSource(name)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("%-18s %s\n", name, source)
+		}
+		return nil
+	},
+}
+
+var promptsShowCmd = &cobra.Command{
+	Use:   "show <name>",
+	Short: "Print the effective text of a prompt template",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		text, _, err := promptSource(args[0])
+		if err != nil {
+			return err
+		}
+		fmt.Print(text)
+		return nil
+	},
+}
+
+func init() {
+	PromptsCmd.AddCommand(promptsListCmd, promptsShowCmd)
+}
@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	openai "github.com/sashabaranov/go-openai"
+)
+
+// Provider, AI komutlarının konuştuğu LLM arka ucunu soyutlar.
+// *openai.Client bu arayüzü doğrudan karşılar; OpenAI uyumlu her sunucu
+// (Azure OpenAI, Ollama, llama.cpp, vLLM...) aynı istemciyle kullanılabilir.
+type Provider interface {
+	CreateChatCompletion(ctx context.Context, req openai.ChatCompletionRequest) (openai.ChatCompletionResponse, error)
+	CreateChatCompletionStream(ctx context.Context, req openai.ChatCompletionRequest) (*openai.ChatCompletionStream, error)
+}
+
+// providerFactory, global flag'lere ve ortam değişkenlerine göre bir Provider üretir.
+type providerFactory func() (Provider, error)
+
+var providers = map[string]providerFactory{
+	"openai":     newOpenAIProvider,
+	"azure":      newAzureProvider,
+	"compatible": compatibleProvider(""),
+	"ollama":     compatibleProvider("http://localhost:11434/v1"),
+	"llamacpp":   compatibleProvider("http://localhost:8080/v1"),
+}
+
+// NewProvider, --provider flag'inde seçilen arka ucu döndürür.
+func NewProvider() (Provider, error) {
+	factory, ok := providers[strings.ToLower(ProviderName)]
+	if !ok {
+		return nil, usageErrorf("unknown provider %q (available: %s)", ProviderName, strings.Join(providerNames(), ", "))
+	}
+	return factory()
+}
+
+func providerNames() []string {
+	names := make([]string, 0, len(providers))
+	for name := range providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// firstEnv, verilen ortam değişkenlerinden ilk dolu olanı döndürür.
+func firstEnv(keys ...string) string {
+	for _, key := range keys {
+		if v := os.Getenv(key); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// api.openai.com
+func newOpenAIProvider() (Provider, error) {
+	apiKey := os.Getenv("OPENAI_API_KEY")
+	if apiKey == "" {
+		return nil, &AIError{Kind: AIErrorAuth, Err: fmt.Errorf("OPENAI_API_KEY environment variable not set")}
+	}
+
+	config := openai.DefaultConfig(apiKey)
+	config.HTTPClient = newHTTPClient()
+	if BaseURL != "" {
+		config.BaseURL = BaseURL
+	}
+	return openai.NewClientWithConfig(config), nil
+}
+
+// Azure OpenAI: --model, Azure tarafındaki deployment adına eşlenir.
+func newAzureProvider() (Provider, error) {
+	apiKey := firstEnv("AZURE_OPENAI_API_KEY", "OPENAI_API_KEY")
+	if apiKey == "" {
+		return nil, &AIError{Kind: AIErrorAuth, Err: fmt.Errorf("AZURE_OPENAI_API_KEY environment variable not set")}
+	}
+
+	endpoint := BaseURL
+	if endpoint == "" {
+		endpoint = os.Getenv("AZURE_OPENAI_ENDPOINT")
+	}
+	if endpoint == "" {
+		return nil, usageErrorf("azure endpoint not set (use --base-url or AZURE_OPENAI_ENDPOINT)")
+	}
+
+	config := openai.DefaultAzureConfig(apiKey, endpoint)
+	config.HTTPClient = newHTTPClient()
+	if APIVersion != "" {
+		config.APIVersion = APIVersion
+	}
+	return openai.NewClientWithConfig(config), nil
+}
+
+// OpenAI uyumlu herhangi bir endpoint (Ollama, llama.cpp server, vLLM...).
+// Yerel sunucular genelde API anahtarı istemez, bu yüzden anahtar opsiyoneldir.
+// defaultURL boşsa --base-url veya KUBE_AI_BASE_URL zorunludur.
+func compatibleProvider(defaultURL string) providerFactory {
+	return func() (Provider, error) {
+		endpoint := BaseURL
+		if endpoint == "" {
+			endpoint = firstEnv("KUBE_AI_BASE_URL", "OPENAI_BASE_URL")
+		}
+		if endpoint == "" {
+			endpoint = defaultURL
+		}
+		if endpoint == "" {
+			return nil, usageErrorf("base URL not set for provider %q (use --base-url or KUBE_AI_BASE_URL)", ProviderName)
+		}
+
+		config := openai.DefaultConfig(firstEnv("KUBE_AI_API_KEY", "OPENAI_API_KEY"))
+		config.BaseURL = endpoint
+		config.HTTPClient = newHTTPClient()
+		return openai.NewClientWithConfig(config), nil
+	}
+}
@@ -0,0 +1,516 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Pod Security Standards seviyeleri. restricted, baseline'ın tüm
+// kontrollerini de içerir.
+const (
+	PSSBaseline   = "baseline"
+	PSSRestricted = "restricted"
+)
+
+// pssControl, Pod Security Standards'ın tek bir kontrolüdür.
+type pssControl struct {
+	ID    string // bulgu ruleId'si, örn. pss-host-namespaces
+	Name  string // belgedeki kontrol adı
+	Level string
+	check func(t *podTemplate) []Finding
+}
+
+// pssControls, https://kubernetes.io/docs/concepts/security/pod-security-standards/
+// belgesindeki kontrollerdir (Linux pod'ları için).
+var pssControls = []pssControl{
+	{"pss-host-process", "HostProcess", PSSBaseline, pssHostProcess},
+	{"pss-host-namespaces", "Host Namespaces", PSSBaseline, pssHostNamespaces},
+	{"pss-privileged", "Privileged Containers", PSSBaseline, pssPrivileged},
+	{"pss-capabilities", "Capabilities", PSSBaseline, pssBaselineCapabilities},
+	{"pss-host-path-volumes", "HostPath Volumes", PSSBaseline, pssHostPathVolumes},
+	{"pss-host-ports", "Host Ports", PSSBaseline, pssHostPorts},
+	{"pss-apparmor", "AppArmor", PSSBaseline, pssAppArmor},
+	{"pss-selinux", "SELinux", PSSBaseline, pssSELinux},
+	{"pss-proc-mount", "/proc Mount Type", PSSBaseline, pssProcMount},
+	{"pss-seccomp", "Seccomp", PSSBaseline, pssBaselineSeccomp},
+	{"pss-sysctls", "Sysctls", PSSBaseline, pssSysctls},
+	{"pss-volume-types", "Volume Types", PSSRestricted, pssVolumeTypes},
+	{"pss-privilege-escalation", "Privilege Escalation", PSSRestricted, pssPrivilegeEscalation},
+	{"pss-run-as-non-root", "Running as Non-root", PSSRestricted, pssRunAsNonRoot},
+	{"pss-run-as-user", "Running as Non-root user", PSSRestricted, pssRunAsUser},
+	{"pss-restricted-seccomp", "Seccomp", PSSRestricted, pssRestrictedSeccomp},
+	{"pss-restricted-capabilities", "Capabilities", PSSRestricted, pssRestrictedCapabilities},
+}
+
+// isPSSControl, ruleId'nin bir PSS kontrolüne ait olup olmadığını söyler.
+func isPSSControl(id string) bool {
+	for _, control := range pssControls {
+		if control.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// pssCoveredRules, bir PSS kontrolünün kapsadığı dahili kurallardır. Aynı
+// iş yükünün aynı alanını işaretleyen dahili bulgu, PSS bulgusunun tekrarıdır.
+var pssCoveredRules = map[string][]string{
+	"privileged-container": {"pss-privileged"},
+	"host-network":         {"pss-host-namespaces"},
+	"host-path-volume":     {"pss-host-path-volumes"},
+	"run-as-root":          {"pss-run-as-user", "pss-run-as-non-root"},
+}
+
+// mergePSSFindings, dahili kural bulgularını PSS bulgularıyla birleştirir;
+// bir PSS kontrolünün zaten bildirdiği dahili bulgular atılır.
+func mergePSSFindings(rules, pss []Finding) []Finding {
+	covered := make(map[string]bool)
+	for _, f := range pss {
+		covered[f.RuleID+"|"+f.Resource+"|"+lastField(f.FieldPath)] = true
+	}
+	merged := slices.Clone(pss)
+	for _, f := range rules {
+		duplicate := false
+		for _, control := range pssCoveredRules[f.RuleID] {
+			if covered[control+"|"+f.Resource+"|"+lastField(f.FieldPath)] {
+				duplicate = true
+				break
+			}
+		}
+		if !duplicate {
+			merged = append(merged, f)
+		}
+	}
+	sortFindings(merged)
+	return merged
+}
+
+// lastField, alan yolunun son adıdır; örn. spec.containers[0].securityContext.privileged → privileged.
+// Pod ve container seviyesindeki aynı ayar böylece eşleşir.
+func lastField(path string) string {
+	return path[strings.LastIndex(path, ".")+1:]
+}
+
+// pssVerdict, bir pod tanımının seviyeye uyup uymadığıdır.
+type pssVerdict struct {
+	Resource string
+	Controls []string
+}
+
+// evaluatePSS, manifest'teki pod tanımlarını seviyeye göre değerlendirir.
+// baseline ihlalleri high, yalnızca restricted'a ait ihlaller medium önemlidir.
+func evaluatePSS(objects []map[string]interface{}, level string) ([]Finding, []pssVerdict) {
+	var findings []Finding
+	var verdicts []pssVerdict
+	for _, t := range podTemplates(objects) {
+		verdict := pssVerdict{Resource: t.Resource}
+		for _, control := range pssControls {
+			if level == PSSBaseline && control.Level == PSSRestricted {
+				continue
+			}
+			for _, f := range control.check(&t) {
+				f.RuleID = control.ID
+				f.Resource = t.Resource
+				f.Namespace = t.Namespace
+				f.Source = SourceRule
+				f.Severity = "high"
+				if control.Level == PSSRestricted {
+					f.Severity = "medium"
+				}
+				f.Description = fmt.Sprintf("Violates %s (%s): %s", control.Name, control.Level, f.Description)
+				findings = append(findings, f)
+				if !slices.Contains(verdict.Controls, control.Name) {
+					verdict.Controls = append(verdict.Controls, control.Name)
+				}
+			}
+		}
+		verdicts = append(verdicts, verdict)
+	}
+	sortFindings(findings)
+	return findings, verdicts
+}
+
+// writePSSVerdicts, her pod tanımının seviyeden geçip geçmediğini yazar.
+func writePSSVerdicts(out io.Writer, level string, verdicts []pssVerdict) {
+	if len(verdicts) == 0 {
+		fmt.Fprintf(out, "\n🛡️ Pod Security Standard %q: no Pods or pod templates found in the input.\n", level)
+		return
+	}
+	failed := 0
+	for _, v := range verdicts {
+		if len(v.Controls) > 0 {
+			failed++
+		}
+	}
+	fmt.Fprintf(out, "\n🛡️ Pod Security Standard %q: %d of %d pod template(s) would be rejected.\n", level, failed, len(verdicts))
+	sort.SliceStable(verdicts, func(i, j int) bool { return verdicts[i].Resource < verdicts[j].Resource })
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "RESOURCE\tVERDICT\tVIOLATED CONTROLS")
+	for _, v := range verdicts {
+		if len(v.Controls) == 0 {
+			fmt.Fprintf(w, "%s\tPASS\t-\n", v.Resource)
+			continue
+		}
+		fmt.Fprintf(w, "%s\tFAIL\t%s\n", v.Resource, strings.Join(v.Controls, ", "))
+	}
+	w.Flush()
+}
+
+// setField, tek bir alan değişikliğini öneren düzeltme metnidir.
+func setField(path, value string) string {
+	return fmt.Sprintf("Set %s: %s", path, value)
+}
+
+// removeField, alanın kaldırılmasını öneren düzeltme metnidir.
+func removeField(path string) string {
+	return "Remove " + path
+}
+
+func pssHostProcess(t *podTemplate) []Finding {
+	var findings []Finding
+	if sc := t.Spec.SecurityContext; sc != nil && sc.WindowsOptions != nil && isTrue(sc.WindowsOptions.HostProcess) {
+		path := t.Path + ".securityContext.windowsOptions.hostProcess"
+		findings = append(findings, Finding{FieldPath: path, Description: "pod sets windowsOptions.hostProcess: true.", Remediation: removeField(path)})
+	}
+	for _, c := range t.containers() {
+		if sc := c.Container.SecurityContext; sc != nil && sc.WindowsOptions != nil && isTrue(sc.WindowsOptions.HostProcess) {
+			path := c.Path + ".securityContext.windowsOptions.hostProcess"
+			findings = append(findings, Finding{FieldPath: path, Description: fmt.Sprintf("container %q sets windowsOptions.hostProcess: true.", c.Container.Name), Remediation: removeField(path)})
+		}
+	}
+	return findings
+}
+
+func pssHostNamespaces(t *podTemplate) []Finding {
+	var findings []Finding
+	for _, ns := range []struct {
+		field string
+		set   bool
+	}{{"hostNetwork", t.Spec.HostNetwork}, {"hostPID", t.Spec.HostPID}, {"hostIPC", t.Spec.HostIPC}} {
+		if ns.set {
+			path := t.Path + "." + ns.field
+			findings = append(findings, Finding{FieldPath: path, Description: fmt.Sprintf("pod sets %s: true.", ns.field), Remediation: removeField(path)})
+		}
+	}
+	return findings
+}
+
+func pssPrivileged(t *podTemplate) []Finding {
+	var findings []Finding
+	for _, c := range t.containers() {
+		if sc := c.Container.SecurityContext; sc != nil && isTrue(sc.Privileged) {
+			path := c.Path + ".securityContext.privileged"
+			findings = append(findings, Finding{FieldPath: path, Description: fmt.Sprintf("container %q is privileged.", c.Container.Name), Remediation: setField(path, "false")})
+		}
+	}
+	return findings
+}
+
+// baselineCapabilities, baseline seviyesinin eklenmesine izin verdiği yetkilerdir.
+var baselineCapabilities = []string{
+	"AUDIT_WRITE", "CHOWN", "DAC_OVERRIDE", "FOWNER", "FSETID", "KILL", "MKNOD",
+	"NET_BIND_SERVICE", "SETFCAP", "SETGID", "SETPCAP", "SETUID", "SYS_CHROOT",
+}
+
+func pssBaselineCapabilities(t *podTemplate) []Finding {
+	return capabilityFindings(t, func(capability string) bool {
+		return !slices.Contains(baselineCapabilities, capability)
+	})
+}
+
+// capabilityFindings, forbidden'ın yasakladığı eklenen yetkileri bulur.
+func capabilityFindings(t *podTemplate, forbidden func(capability string) bool) []Finding {
+	var findings []Finding
+	for _, c := range t.containers() {
+		sc := c.Container.SecurityContext
+		if sc == nil || sc.Capabilities == nil {
+			continue
+		}
+		var extra []string
+		for _, capability := range sc.Capabilities.Add {
+			if forbidden(string(capability)) {
+				extra = append(extra, string(capability))
+			}
+		}
+		if len(extra) > 0 {
+			path := c.Path + ".securityContext.capabilities.add"
+			findings = append(findings, Finding{
+				FieldPath:   path,
+				Description: fmt.Sprintf("container %q adds capabilities %s.", c.Container.Name, strings.Join(extra, ", ")),
+				Remediation: fmt.Sprintf("Remove %s from %s", strings.Join(extra, ", "), path),
+			})
+		}
+	}
+	return findings
+}
+
+func pssHostPathVolumes(t *podTemplate) []Finding {
+	var findings []Finding
+	for i, v := range t.Spec.Volumes {
+		if v.HostPath != nil {
+			path := fmt.Sprintf("%s.volumes[%d]", t.Path, i)
+			findings = append(findings, Finding{
+				FieldPath:   path + ".hostPath",
+				Description: fmt.Sprintf("volume %q is a hostPath volume.", v.Name),
+				Remediation: fmt.Sprintf("Replace %s.hostPath with a persistentVolumeClaim, configMap, secret or emptyDir volume", path),
+			})
+		}
+	}
+	return findings
+}
+
+func pssHostPorts(t *podTemplate) []Finding {
+	var findings []Finding
+	for _, c := range t.containers() {
+		for i, port := range c.Container.Ports {
+			if port.HostPort != 0 {
+				path := fmt.Sprintf("%s.ports[%d].hostPort", c.Path, i)
+				findings = append(findings, Finding{FieldPath: path, Description: fmt.Sprintf("container %q uses host port %d.", c.Container.Name, port.HostPort), Remediation: removeField(path)})
+			}
+		}
+	}
+	return findings
+}
+
+const appArmorAnnotationPrefix = "container.apparmor.security.beta.kubernetes.io/"
+
+func pssAppArmor(t *podTemplate) []Finding {
+	var findings []Finding
+	if sc := t.Spec.SecurityContext; sc != nil && sc.AppArmorProfile != nil && sc.AppArmorProfile.Type == corev1.AppArmorProfileTypeUnconfined {
+		path := t.Path + ".securityContext.appArmorProfile.type"
+		findings = append(findings, Finding{FieldPath: path, Description: "pod sets an Unconfined AppArmor profile.", Remediation: setField(path, "RuntimeDefault")})
+	}
+	for _, c := range t.containers() {
+		if sc := c.Container.SecurityContext; sc != nil && sc.AppArmorProfile != nil && sc.AppArmorProfile.Type == corev1.AppArmorProfileTypeUnconfined {
+			path := c.Path + ".securityContext.appArmorProfile.type"
+			findings = append(findings, Finding{FieldPath: path, Description: fmt.Sprintf("container %q sets an Unconfined AppArmor profile.", c.Container.Name), Remediation: setField(path, "RuntimeDefault")})
+		}
+	}
+	keys := make([]string, 0, len(t.Annotations))
+	for key := range t.Annotations {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		value := t.Annotations[key]
+		if strings.HasPrefix(key, appArmorAnnotationPrefix) && value != "runtime/default" && !strings.HasPrefix(value, "localhost/") {
+			path := t.MetaPath + ".annotations." + key
+			findings = append(findings, Finding{FieldPath: path, Description: fmt.Sprintf("annotation %s sets AppArmor profile %q.", key, value), Remediation: setField(path, "runtime/default")})
+		}
+	}
+	return findings
+}
+
+// allowedSELinuxTypes, baseline seviyesinin izin verdiği SELinux tipleridir.
+var allowedSELinuxTypes = []string{"", "container_t", "container_init_t", "container_kvm_t", "container_engine_t"}
+
+func pssSELinux(t *podTemplate) []Finding {
+	check := func(options *corev1.SELinuxOptions, path, who string) []Finding {
+		if options == nil {
+			return nil
+		}
+		var findings []Finding
+		if !slices.Contains(allowedSELinuxTypes, options.Type) {
+			findings = append(findings, Finding{FieldPath: path + ".type", Description: fmt.Sprintf("%s sets SELinux type %q.", who, options.Type), Remediation: removeField(path + ".type")})
+		}
+		if options.User != "" {
+			findings = append(findings, Finding{FieldPath: path + ".user", Description: fmt.Sprintf("%s sets a custom SELinux user.", who), Remediation: removeField(path + ".user")})
+		}
+		if options.Role != "" {
+			findings = append(findings, Finding{FieldPath: path + ".role", Description: fmt.Sprintf("%s sets a custom SELinux role.", who), Remediation: removeField(path + ".role")})
+		}
+		return findings
+	}
+
+	var findings []Finding
+	if sc := t.Spec.SecurityContext; sc != nil {
+		findings = append(findings, check(sc.SELinuxOptions, t.Path+".securityContext.seLinuxOptions", "pod")...)
+	}
+	for _, c := range t.containers() {
+		if sc := c.Container.SecurityContext; sc != nil {
+			findings = append(findings, check(sc.SELinuxOptions, c.Path+".securityContext.seLinuxOptions", fmt.Sprintf("container %q", c.Container.Name))...)
+		}
+	}
+	return findings
+}
+
+func pssProcMount(t *podTemplate) []Finding {
+	var findings []Finding
+	for _, c := range t.containers() {
+		if sc := c.Container.SecurityContext; sc != nil && sc.ProcMount != nil && *sc.ProcMount != corev1.DefaultProcMount {
+			path := c.Path + ".securityContext.procMount"
+			findings = append(findings, Finding{FieldPath: path, Description: fmt.Sprintf("container %q sets procMount: %s.", c.Container.Name, *sc.ProcMount), Remediation: removeField(path)})
+		}
+	}
+	return findings
+}
+
+func pssBaselineSeccomp(t *podTemplate) []Finding {
+	var findings []Finding
+	if sc := t.Spec.SecurityContext; sc != nil && sc.SeccompProfile != nil && sc.SeccompProfile.Type == corev1.SeccompProfileTypeUnconfined {
+		path := t.Path + ".securityContext.seccompProfile.type"
+		findings = append(findings, Finding{FieldPath: path, Description: "pod sets an Unconfined seccomp profile.", Remediation: setField(path, "RuntimeDefault")})
+	}
+	for _, c := range t.containers() {
+		if sc := c.Container.SecurityContext; sc != nil && sc.SeccompProfile != nil && sc.SeccompProfile.Type == corev1.SeccompProfileTypeUnconfined {
+			path := c.Path + ".securityContext.seccompProfile.type"
+			findings = append(findings, Finding{FieldPath: path, Description: fmt.Sprintf("container %q sets an Unconfined seccomp profile.", c.Container.Name), Remediation: setField(path, "RuntimeDefault")})
+		}
+	}
+	return findings
+}
+
+// safeSysctls, baseline seviyesinin izin verdiği sysctl'lerdir.
+var safeSysctls = []string{
+	"kernel.shm_rmid_forced", "net.ipv4.ip_local_port_range", "net.ipv4.ip_unprivileged_port_start",
+	"net.ipv4.tcp_syncookies", "net.ipv4.ping_group_range", "net.ipv4.ip_local_reserved_ports",
+	"net.ipv4.tcp_keepalive_time", "net.ipv4.tcp_fin_timeout", "net.ipv4.tcp_keepalive_intvl",
+	"net.ipv4.tcp_keepalive_probes",
+}
+
+func pssSysctls(t *podTemplate) []Finding {
+	var findings []Finding
+	if sc := t.Spec.SecurityContext; sc != nil {
+		for i, sysctl := range sc.Sysctls {
+			if !slices.Contains(safeSysctls, sysctl.Name) {
+				path := fmt.Sprintf("%s.securityContext.sysctls[%d]", t.Path, i)
+				findings = append(findings, Finding{FieldPath: path, Description: fmt.Sprintf("pod sets unsafe sysctl %s.", sysctl.Name), Remediation: removeField(path)})
+			}
+		}
+	}
+	return findings
+}
+
+func pssVolumeTypes(t *podTemplate) []Finding {
+	var findings []Finding
+	for i, v := range t.Spec.Volumes {
+		source := v.VolumeSource
+		if source.ConfigMap != nil || source.CSI != nil || source.DownwardAPI != nil || source.EmptyDir != nil ||
+			source.Ephemeral != nil || source.PersistentVolumeClaim != nil || source.Projected != nil || source.Secret != nil {
+			continue
+		}
+		// hostPath zaten baseline kontrolünde bildirilir
+		if source.HostPath != nil {
+			continue
+		}
+		path := fmt.Sprintf("%s.volumes[%d]", t.Path, i)
+		volumeType := volumeSourceType(source)
+		findings = append(findings, Finding{
+			FieldPath:   path + "." + volumeType,
+			Description: fmt.Sprintf("volume %q uses the %s volume type.", v.Name, volumeType),
+			Remediation: fmt.Sprintf("Replace %s.%s with a persistentVolumeClaim, csi or ephemeral volume", path, volumeType),
+		})
+	}
+	return findings
+}
+
+// volumeSourceType, volume kaynağının YAML'daki alan adını döndürür (örn. nfs).
+func volumeSourceType(source corev1.VolumeSource) string {
+	var fields map[string]interface{}
+	if decodeInto(source, &fields) == nil {
+		for name := range fields {
+			return name
+		}
+	}
+	return "unknown"
+}
+
+func pssPrivilegeEscalation(t *podTemplate) []Finding {
+	var findings []Finding
+	for _, c := range t.containers() {
+		if sc := c.Container.SecurityContext; sc == nil || sc.AllowPrivilegeEscalation == nil || *sc.AllowPrivilegeEscalation {
+			path := c.Path + ".securityContext.allowPrivilegeEscalation"
+			findings = append(findings, Finding{FieldPath: path, Description: fmt.Sprintf("container %q does not set allowPrivilegeEscalation: false.", c.Container.Name), Remediation: setField(path, "false")})
+		}
+	}
+	return findings
+}
+
+func pssRunAsNonRoot(t *podTemplate) []Finding {
+	podNonRoot := t.Spec.SecurityContext != nil && isTrue(t.Spec.SecurityContext.RunAsNonRoot)
+	var findings []Finding
+	var unset []string
+	for _, c := range t.containers() {
+		sc := c.Container.SecurityContext
+		switch {
+		case sc != nil && sc.RunAsNonRoot != nil && !*sc.RunAsNonRoot:
+			path := c.Path + ".securityContext.runAsNonRoot"
+			findings = append(findings, Finding{FieldPath: path, Description: fmt.Sprintf("container %q sets runAsNonRoot: false.", c.Container.Name), Remediation: setField(path, "true")})
+		case sc == nil || sc.RunAsNonRoot == nil:
+			if !podNonRoot {
+				unset = append(unset, c.Container.Name)
+			}
+		}
+	}
+	// Tek bir pod seviyesi alan tüm container'ları düzeltir
+	if len(unset) > 0 {
+		path := t.Path + ".securityContext.runAsNonRoot"
+		findings = append(findings, Finding{
+			FieldPath:   path,
+			Description: fmt.Sprintf("runAsNonRoot is not true for container(s) %s.", strings.Join(unset, ", ")),
+			Remediation: setField(path, "true"),
+		})
+	}
+	return findings
+}
+
+func pssRunAsUser(t *podTemplate) []Finding {
+	var findings []Finding
+	if sc := t.Spec.SecurityContext; sc != nil && sc.RunAsUser != nil && *sc.RunAsUser == 0 {
+		path := t.Path + ".securityContext.runAsUser"
+		findings = append(findings, Finding{FieldPath: path, Description: "pod sets runAsUser: 0.", Remediation: setField(path, "a non-zero UID, e.g. 10001")})
+	}
+	for _, c := range t.containers() {
+		if sc := c.Container.SecurityContext; sc != nil && sc.RunAsUser != nil && *sc.RunAsUser == 0 {
+			path := c.Path + ".securityContext.runAsUser"
+			findings = append(findings, Finding{FieldPath: path, Description: fmt.Sprintf("container %q sets runAsUser: 0.", c.Container.Name), Remediation: setField(path, "a non-zero UID, e.g. 10001")})
+		}
+	}
+	return findings
+}
+
+func pssRestrictedSeccomp(t *podTemplate) []Finding {
+	podSet := t.Spec.SecurityContext != nil && t.Spec.SecurityContext.SeccompProfile != nil
+	var unset []string
+	for _, c := range t.containers() {
+		// Unconfined baseline kontrolünde bildirilir; burada yalnızca eksik profil aranır
+		if sc := c.Container.SecurityContext; (sc == nil || sc.SeccompProfile == nil) && !podSet {
+			unset = append(unset, c.Container.Name)
+		}
+	}
+	if len(unset) == 0 {
+		return nil
+	}
+	path := t.Path + ".securityContext.seccompProfile.type"
+	return []Finding{{
+		FieldPath:   path,
+		Description: fmt.Sprintf("no seccomp profile is set for container(s) %s.", strings.Join(unset, ", ")),
+		Remediation: setField(path, "RuntimeDefault"),
+	}}
+}
+
+func pssRestrictedCapabilities(t *podTemplate) []Finding {
+	// baseline dışındaki eklemeler zaten baseline kontrolünde bildirilir
+	findings := capabilityFindings(t, func(capability string) bool {
+		return capability != "NET_BIND_SERVICE" && slices.Contains(baselineCapabilities, capability)
+	})
+	for _, c := range t.containers() {
+		sc := c.Container.SecurityContext
+		if sc != nil && sc.Capabilities != nil && slices.Contains(sc.Capabilities.Drop, "ALL") {
+			continue
+		}
+		path := c.Path + ".securityContext.capabilities.drop"
+		findings = append(findings, Finding{FieldPath: path, Description: fmt.Sprintf("container %q does not drop ALL capabilities.", c.Container.Name), Remediation: setField(path, `["ALL"]`)})
+	}
+	return findings
+}
+
+func isTrue(b *bool) bool {
+	return b != nil && *b
+}
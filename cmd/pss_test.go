@@ -0,0 +1,251 @@
+package cmd
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+	"testing"
+)
+
+const restrictedPod = `apiVersion: v1
+kind: Pod
+metadata:
+  name: hardened
+spec:
+  securityContext:
+    runAsNonRoot: true
+    seccompProfile: {type: RuntimeDefault}
+  containers:
+  - name: app
+    image: app:1
+    securityContext:
+      allowPrivilegeEscalation: false
+      capabilities:
+        drop: [ALL]
+        add: [NET_BIND_SERVICE]
+`
+
+const plainDeployment = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  template:
+    spec:
+      volumes:
+      - name: shared
+        nfs: {server: nfs.local, path: /exports}
+      containers:
+      - name: web
+        image: nginx:1.27
+`
+
+func TestEvaluatePSS(t *testing.T) {
+	tests := []struct {
+		name     string
+		manifest string
+		level    string
+		want     []string
+	}{
+		{
+			name:     "restricted pod passes restricted",
+			manifest: restrictedPod,
+			level:    PSSRestricted,
+			want:     []string{},
+		},
+		{
+			name: "baseline violations",
+			manifest: `apiVersion: v1
+kind: Pod
+metadata:
+  name: node-agent
+spec:
+  hostPID: true
+  securityContext:
+    seccompProfile: {type: Unconfined}
+    sysctls:
+    - {name: kernel.msgmax, value: "65536"}
+  containers:
+  - name: agent
+    image: agent:1
+    ports:
+    - {containerPort: 9100, hostPort: 9100}
+    securityContext:
+      privileged: true
+      capabilities:
+        add: [SYS_ADMIN, CHOWN]
+`,
+			level: PSSBaseline,
+			want: []string{
+				"pss-host-namespaces high pod/node-agent spec.hostPID",
+				"pss-privileged high pod/node-agent spec.containers[0].securityContext.privileged",
+				"pss-capabilities high pod/node-agent spec.containers[0].securityContext.capabilities.add",
+				"pss-host-ports high pod/node-agent spec.containers[0].ports[0].hostPort",
+				"pss-sysctls high pod/node-agent spec.securityContext.sysctls[0]",
+				"pss-seccomp high pod/node-agent spec.securityContext.seccompProfile.type",
+			},
+		},
+		{
+			name:     "plain deployment passes baseline",
+			manifest: plainDeployment,
+			level:    PSSBaseline,
+			want:     []string{},
+		},
+		{
+			name:     "plain deployment fails restricted",
+			manifest: plainDeployment,
+			level:    PSSRestricted,
+			want: []string{
+				"pss-volume-types medium deployment/web spec.template.spec.volumes[0].nfs",
+				"pss-privilege-escalation medium deployment/web spec.template.spec.containers[0].securityContext.allowPrivilegeEscalation",
+				"pss-run-as-non-root medium deployment/web spec.template.spec.securityContext.runAsNonRoot",
+				"pss-restricted-seccomp medium deployment/web spec.template.spec.securityContext.seccompProfile.type",
+				"pss-restricted-capabilities medium deployment/web spec.template.spec.containers[0].securityContext.capabilities.drop",
+			},
+		},
+		{
+			name: "restricted capabilities and root user",
+			manifest: `apiVersion: v1
+kind: Pod
+metadata:
+  name: worker
+spec:
+  securityContext:
+    runAsNonRoot: true
+    seccompProfile: {type: RuntimeDefault}
+  containers:
+  - name: worker
+    image: worker:1
+    securityContext:
+      runAsUser: 0
+      allowPrivilegeEscalation: false
+      capabilities:
+        drop: [ALL]
+        add: [CHOWN]
+`,
+			level: PSSRestricted,
+			want: []string{
+				"pss-run-as-user medium pod/worker spec.containers[0].securityContext.runAsUser",
+				"pss-restricted-capabilities medium pod/worker spec.containers[0].securityContext.capabilities.add",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			quietStatus(t)
+			findings, _ := evaluatePSS(manifestObjects(tt.manifest, "test.yaml"), tt.level)
+			assertFindings(t, findings, tt.want)
+		})
+	}
+}
+
+func TestMergePSSFindings(t *testing.T) {
+	manifest := `apiVersion: v1
+kind: Pod
+metadata:
+  name: node-agent
+spec:
+  hostNetwork: true
+  securityContext: {runAsUser: 0}
+  volumes:
+  - name: root
+    hostPath: {path: /}
+  containers:
+  - name: agent
+    image: agent:latest
+    securityContext: {privileged: true}
+`
+	tests := []struct {
+		name  string
+		level string
+		want  []string
+	}{
+		{
+			name:  "baseline keeps run-as-root and the rules PSS does not cover",
+			level: PSSBaseline,
+			want: []string{
+				"pss-host-namespaces high pod/node-agent spec.hostNetwork",
+				"pss-host-path-volumes high pod/node-agent spec.volumes[0].hostPath",
+				"pss-privileged high pod/node-agent spec.containers[0].securityContext.privileged",
+				"run-as-root high pod/node-agent spec.securityContext.runAsUser",
+				"image-latest-tag medium pod/node-agent spec.containers[0].image",
+				"missing-resource-limits medium pod/node-agent spec.containers[0].resources.limits",
+				"automount-service-account-token low pod/node-agent spec.automountServiceAccountToken",
+				"missing-probes low pod/node-agent spec.containers[0].livenessProbe",
+			},
+		},
+		{
+			name:  "restricted also covers run-as-root",
+			level: PSSRestricted,
+			want: []string{
+				"pss-host-namespaces high pod/node-agent spec.hostNetwork",
+				"pss-host-path-volumes high pod/node-agent spec.volumes[0].hostPath",
+				"pss-privileged high pod/node-agent spec.containers[0].securityContext.privileged",
+				"image-latest-tag medium pod/node-agent spec.containers[0].image",
+				"missing-resource-limits medium pod/node-agent spec.containers[0].resources.limits",
+				"pss-privilege-escalation medium pod/node-agent spec.containers[0].securityContext.allowPrivilegeEscalation",
+				"pss-restricted-capabilities medium pod/node-agent spec.containers[0].securityContext.capabilities.drop",
+				"pss-restricted-seccomp medium pod/node-agent spec.securityContext.seccompProfile.type",
+				"pss-run-as-non-root medium pod/node-agent spec.securityContext.runAsNonRoot",
+				"pss-run-as-user medium pod/node-agent spec.securityContext.runAsUser",
+				"automount-service-account-token low pod/node-agent spec.automountServiceAccountToken",
+				"missing-probes low pod/node-agent spec.containers[0].livenessProbe",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			quietStatus(t)
+			objects := manifestObjects(manifest, "test.yaml")
+			pss, _ := evaluatePSS(objects, tt.level)
+			assertFindings(t, mergePSSFindings(runRules(objects), pss), tt.want)
+		})
+	}
+}
+
+func TestPSSControlIDsAreUnique(t *testing.T) {
+	seen := map[string]bool{}
+	for _, control := range pssControls {
+		if seen[control.ID] {
+			t.Errorf("control ID %s is used twice", control.ID)
+		}
+		seen[control.ID] = true
+		if !isPSSControl(control.ID) {
+			t.Errorf("isPSSControl(%s) = false", control.ID)
+		}
+	}
+	if isPSSControl("host-network") {
+		t.Error("isPSSControl(host-network) = true for a built-in rule")
+	}
+}
+
+func TestWritePSSVerdicts(t *testing.T) {
+	quietStatus(t)
+	objects := manifestObjects(restrictedPod+"---\n"+plainDeployment, "test.yaml")
+	_, verdicts := evaluatePSS(objects, PSSRestricted)
+
+	var out bytes.Buffer
+	writePSSVerdicts(&out, PSSRestricted, verdicts)
+	text := out.String()
+	if !strings.Contains(text, "1 of 2 pod template(s) would be rejected") {
+		t.Errorf("summary line missing:\n%s", text)
+	}
+	var rows []string
+	for _, line := range strings.Split(strings.TrimSpace(text), "\n") {
+		if fields := strings.Fields(line); len(fields) >= 2 && strings.Contains(fields[0], "/") {
+			rows = append(rows, fields[0]+" "+fields[1])
+		}
+	}
+	if want := []string{"deployment/web FAIL", "pod/hardened PASS"}; !slices.Equal(rows, want) {
+		t.Errorf("verdict rows = %q, want %q", rows, want)
+	}
+	if !strings.Contains(text, "Volume Types, Privilege Escalation, Running as Non-root, Seccomp, Capabilities") {
+		t.Errorf("violated controls missing:\n%s", text)
+	}
+
+	out.Reset()
+	writePSSVerdicts(&out, PSSBaseline, nil)
+	if !strings.Contains(out.String(), "no Pods or pod templates found") {
+		t.Errorf("empty verdicts output = %q", out.String())
+	}
+}
@@ -0,0 +1,449 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// rbacObjects, RBAC analizinin girdisidir.
+type rbacObjects struct {
+	Roles               []rbacv1.Role
+	ClusterRoles        []rbacv1.ClusterRole
+	RoleBindings        []rbacv1.RoleBinding
+	ClusterRoleBindings []rbacv1.ClusterRoleBinding
+}
+
+// rbacFromManifests, manifest nesnelerinden RBAC türlerini ayıklar; diğer türler atlanır.
+func rbacFromManifests(objects []map[string]interface{}) *rbacObjects {
+	in := &rbacObjects{}
+	for _, obj := range objects {
+		switch obj["kind"] {
+		case "Role":
+			var role rbacv1.Role
+			if decodeInto(obj, &role) == nil {
+				in.Roles = append(in.Roles, role)
+			}
+		case "ClusterRole":
+			var role rbacv1.ClusterRole
+			if decodeInto(obj, &role) == nil {
+				in.ClusterRoles = append(in.ClusterRoles, role)
+			}
+		case "RoleBinding":
+			var binding rbacv1.RoleBinding
+			if decodeInto(obj, &binding) == nil {
+				in.RoleBindings = append(in.RoleBindings, binding)
+			}
+		case "ClusterRoleBinding":
+			var binding rbacv1.ClusterRoleBinding
+			if decodeInto(obj, &binding) == nil {
+				in.ClusterRoleBindings = append(in.ClusterRoleBindings, binding)
+			}
+		}
+	}
+	return in
+}
+
+// LoadRBAC, cluster'daki ClusterRole/ClusterRoleBinding'leri ve namespace'teki
+// (namespace boşsa tüm namespace'lerdeki) Role/RoleBinding'leri okur.
+func (c *Cluster) LoadRBAC(ctx context.Context, namespace string) (*rbacObjects, error) {
+	api := c.Clientset.RbacV1()
+	clusterRoles, err := api.ClusterRoles().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, clusterErrorf("failed to list clusterroles: %w", err)
+	}
+	clusterBindings, err := api.ClusterRoleBindings().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, clusterErrorf("failed to list clusterrolebindings: %w", err)
+	}
+	roles, err := api.Roles(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, clusterErrorf("failed to list roles: %w", err)
+	}
+	bindings, err := api.RoleBindings(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, clusterErrorf("failed to list rolebindings: %w", err)
+	}
+	return &rbacObjects{
+		Roles:               roles.Items,
+		ClusterRoles:        clusterRoles.Items,
+		RoleBindings:        bindings.Items,
+		ClusterRoleBindings: clusterBindings.Items,
+	}, nil
+}
+
+// rbacGrant, bir subject'e bir binding üzerinden verilen rol kurallarıdır.
+type rbacGrant struct {
+	Subject string // örn. "ServiceAccount ci/deployer", "User alice"
+	Scope   string // namespace; cluster genelinde ise boş
+	Role    string // kind/name, örn. clusterrole/admin
+	Binding string // örn. "RoleBinding ci/deployer"
+	// BindingRef ve SubjectIndex bulgunun kimliğidir (örn. clusterrolebinding/ops,
+	// subjects[1]); aynı rolün farklı subject'lere verilmesi ayrı bulgulardır.
+	BindingRef   string
+	SubjectIndex int
+	Rules        []rbacv1.PolicyRule
+	Missing      bool // binding'in gösterdiği rol bulunamadı
+}
+
+// scopeText, grant'ın kapsamını okunabilir biçimde döndürür.
+func (g rbacGrant) scopeText() string {
+	if g.Scope == "" {
+		return "cluster-wide"
+	}
+	return "in namespace " + g.Scope
+}
+
+// subjectName, subject'i "Kind namespace/name" biçiminde adlandırır.
+func subjectName(subject rbacv1.Subject, bindingNamespace string) string {
+	if subject.Kind == rbacv1.ServiceAccountKind {
+		namespace := subject.Namespace
+		if namespace == "" {
+			namespace = bindingNamespace
+		}
+		return fmt.Sprintf("%s %s/%s", subject.Kind, namespace, subject.Name)
+	}
+	return subject.Kind + " " + subject.Name
+}
+
+// rbacDefaultsLabel, Kubernetes'in varsayılan RBAC nesnelerine koyduğu label'dır
+// (kubernetes.io/bootstrapping=rbac-defaults).
+const rbacDefaultsLabel = "kubernetes.io/bootstrapping"
+
+// isSystemBinding, binding'in Kubernetes'in kendisine ait olup olmadığını söyler:
+// adı system: önekli veya rbac-defaults label'lı binding'ler.
+func isSystemBinding(meta metav1.ObjectMeta) bool {
+	return strings.HasPrefix(meta.Name, "system:") || meta.Labels[rbacDefaultsLabel] == "rbac-defaults"
+}
+
+// effectiveGrants, binding'leri çözer ve her subject'in aldığı kuralları döndürür.
+// includeSystem false ise Kubernetes'in kendi binding'leri atlanır. Subject'lere
+// bakılmaz: system:authenticated veya system:anonymous'a yetki veren kullanıcı
+// binding'leri en tehlikeli bulgulardandır.
+func effectiveGrants(in *rbacObjects, includeSystem bool) []rbacGrant {
+	clusterRoles := make(map[string]*rbacv1.ClusterRole)
+	for i := range in.ClusterRoles {
+		clusterRoles[in.ClusterRoles[i].Name] = &in.ClusterRoles[i]
+	}
+	roles := make(map[string]*rbacv1.Role)
+	for i := range in.Roles {
+		roles[in.Roles[i].Namespace+"/"+in.Roles[i].Name] = &in.Roles[i]
+	}
+
+	resolve := func(ref rbacv1.RoleRef, namespace string) (string, []rbacv1.PolicyRule, bool) {
+		if ref.Kind == "ClusterRole" {
+			role, ok := clusterRoles[ref.Name]
+			if !ok {
+				return "clusterrole/" + ref.Name, nil, false
+			}
+			return "clusterrole/" + ref.Name, role.Rules, true
+		}
+		role, ok := roles[namespace+"/"+ref.Name]
+		if !ok {
+			return "role/" + ref.Name, nil, false
+		}
+		return "role/" + ref.Name, role.Rules, true
+	}
+
+	var grants []rbacGrant
+	add := func(binding, bindingRef string, meta metav1.ObjectMeta, ref rbacv1.RoleRef, subjects []rbacv1.Subject) {
+		if !includeSystem && isSystemBinding(meta) {
+			return
+		}
+		role, rules, ok := resolve(ref, meta.Namespace)
+		for i, subject := range subjects {
+			grants = append(grants, rbacGrant{
+				Subject:      subjectName(subject, meta.Namespace),
+				Scope:        meta.Namespace,
+				Role:         role,
+				Binding:      binding,
+				BindingRef:   bindingRef,
+				SubjectIndex: i,
+				Rules:        rules,
+				Missing:      !ok,
+			})
+		}
+	}
+	for _, b := range in.ClusterRoleBindings {
+		b.Namespace = "" // cluster kapsamlıdır; dosyadaki hatalı namespace yok sayılır
+		add("ClusterRoleBinding "+b.Name, "clusterrolebinding/"+b.Name, b.ObjectMeta, b.RoleRef, b.Subjects)
+	}
+	for _, b := range in.RoleBindings {
+		add(fmt.Sprintf("RoleBinding %s/%s", b.Namespace, b.Name), "rolebinding/"+b.Name, b.ObjectMeta, b.RoleRef, b.Subjects)
+	}
+
+	sort.SliceStable(grants, func(i, j int) bool {
+		if grants[i].Subject != grants[j].Subject {
+			return grants[i].Subject < grants[j].Subject
+		}
+		return grants[i].Scope < grants[j].Scope
+	})
+	return grants
+}
+
+// ruleResources, kuralın kaynaklarını resource.group biçiminde listeler.
+func ruleResources(rule rbacv1.PolicyRule) string {
+	if len(rule.NonResourceURLs) > 0 {
+		return strings.Join(rule.NonResourceURLs, ",")
+	}
+	var names []string
+	for _, group := range rule.APIGroups {
+		for _, resource := range rule.Resources {
+			if group != "" {
+				resource += "." + group
+			}
+			names = append(names, resource)
+		}
+	}
+	text := strings.Join(names, ",")
+	if len(rule.ResourceNames) > 0 {
+		text += " [" + strings.Join(rule.ResourceNames, ",") + "]"
+	}
+	return text
+}
+
+// writePermissions, her subject'in etkin yetkilerini tablo olarak yazar.
+func writePermissions(out io.Writer, grants []rbacGrant) {
+	if len(grants) == 0 {
+		fmt.Fprintln(out, "\n👥 No role bindings found.")
+		return
+	}
+	fmt.Fprintln(out, "\n👥 Effective permissions:")
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "SUBJECT\tSCOPE\tVERBS\tRESOURCES\tVIA")
+	for _, g := range grants {
+		scope := g.Scope
+		if scope == "" {
+			scope = "*"
+		}
+		if g.Missing {
+			fmt.Fprintf(w, "%s\t%s\t-\t(%s not found)\t%s\n", g.Subject, scope, g.Role, g.Binding)
+			continue
+		}
+		for _, rule := range g.Rules {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s → %s\n", g.Subject, scope, strings.Join(rule.Verbs, ","), ruleResources(rule), g.Binding, g.Role)
+		}
+	}
+	w.Flush()
+}
+
+// permissionsText, yetki tablosunu AI'a gönderilecek metin olarak döndürür.
+func permissionsText(grants []rbacGrant) string {
+	var buf bytes.Buffer
+	writePermissions(&buf, grants)
+	return strings.TrimSpace(buf.String())
+}
+
+// ruleAllows, kuralın verilen fiillerden birine, API grubuna ve kaynaklardan
+// birine izin verip vermediğini Kubernetes RBAC eşleştirme kurallarıyla kontrol eder.
+func ruleAllows(rule rbacv1.PolicyRule, verbs []string, group string, resources []string) bool {
+	if !slices.ContainsFunc(rule.Verbs, func(v string) bool { return v == rbacv1.VerbAll || slices.Contains(verbs, v) }) {
+		return false
+	}
+	if !slices.ContainsFunc(rule.APIGroups, func(g string) bool { return g == rbacv1.APIGroupAll || g == group }) {
+		return false
+	}
+	return slices.ContainsFunc(rule.Resources, func(r string) bool {
+		for _, resource := range resources {
+			if r == rbacv1.ResourceAll || r == resource {
+				return true
+			}
+			// "*/exec" gibi tüm kaynakların bir alt kaynağı
+			if _, sub, ok := strings.Cut(resource, "/"); ok && r == "*/"+sub {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// rbacCheck, bir yetki kuralındaki yükseltme yolunu arar. check, eşleşirse
+// bulgu metnini ve (boş değilse) kuralın önem derecesini ezen değeri döndürür.
+// namespaced, kuralın bir RoleBinding ile verildiğini söyler; RoleBinding
+// cluster kapsamlı kaynaklara (nodes, users, clusterroles) yetki vermez.
+type rbacCheck struct {
+	ID          string
+	Severity    string
+	Remediation string
+	check       func(rule rbacv1.PolicyRule, namespaced bool) (string, string, bool)
+}
+
+var rbacChecks = []rbacCheck{
+	{
+		ID:          "rbac-wildcard",
+		Severity:    "high",
+		Remediation: "Replace the wildcards with the exact verbs and resources the subject needs.",
+		check: func(rule rbacv1.PolicyRule, namespaced bool) (string, string, bool) {
+			verbs := slices.Contains(rule.Verbs, rbacv1.VerbAll)
+			resources := slices.Contains(rule.Resources, rbacv1.ResourceAll)
+			switch {
+			case isFullWildcard(rule):
+				return "has every verb on every resource", "critical", true
+			case verbs:
+				return "has every verb (*) on " + ruleResources(rule), "", true
+			case resources:
+				return fmt.Sprintf("can %s every resource in API groups %s", strings.Join(rule.Verbs, ","), apiGroupNames(rule.APIGroups)), "", true
+			}
+			return "", "", false
+		},
+	},
+	{
+		ID:          "rbac-escalate-bind",
+		Severity:    "critical",
+		Remediation: "Remove the escalate and bind verbs; only cluster administrators should be able to grant permissions they do not hold.",
+		check: func(rule rbacv1.PolicyRule, namespaced bool) (string, string, bool) {
+			resources := []string{"roles", "clusterroles"}
+			if namespaced {
+				resources = []string{"roles"}
+			}
+			if ruleAllows(rule, []string{"escalate", "bind"}, rbacv1.GroupName, resources) {
+				return "can escalate or bind roles and so grant itself any permission", "", true
+			}
+			return "", "", false
+		},
+	},
+	{
+		ID:          "rbac-impersonate",
+		Severity:    "critical",
+		Remediation: "Remove the impersonate verb, or limit it with resourceNames to specific identities.",
+		check: func(rule rbacv1.PolicyRule, namespaced bool) (string, string, bool) {
+			if namespaced {
+				if ruleAllows(rule, []string{"impersonate"}, "", []string{"serviceaccounts"}) {
+					return "can impersonate service accounts", "", true
+				}
+				return "", "", false
+			}
+			if ruleAllows(rule, []string{"impersonate"}, "", []string{"users", "groups", "serviceaccounts"}) ||
+				ruleAllows(rule, []string{"impersonate"}, "authentication.k8s.io", []string{"uids", "userextras/*"}) {
+				return "can impersonate other users, groups or service accounts", "", true
+			}
+			return "", "", false
+		},
+	},
+	{
+		ID:          "rbac-nodes-proxy",
+		Severity:    "critical",
+		Remediation: "Remove access to nodes/proxy; it exposes the kubelet API, including exec into every pod on the node.",
+		check: func(rule rbacv1.PolicyRule, namespaced bool) (string, string, bool) {
+			if !namespaced && ruleAllows(rule, []string{"get", "create"}, "", []string{"nodes/proxy"}) {
+				return "can reach the kubelet API through nodes/proxy", "", true
+			}
+			return "", "", false
+		},
+	},
+	{
+		ID:          "rbac-pods-exec",
+		Severity:    "high",
+		Remediation: "Remove access to pods/exec and pods/attach, or move it to a break-glass role.",
+		check: func(rule rbacv1.PolicyRule, namespaced bool) (string, string, bool) {
+			// WebSocket üzerinden exec için get yeterlidir
+			if ruleAllows(rule, []string{"create", "get"}, "", []string{"pods/exec", "pods/attach"}) {
+				return "can exec or attach into pods and run commands with their service account", "", true
+			}
+			return "", "", false
+		},
+	},
+	{
+		ID:          "rbac-secrets-read",
+		Severity:    "high",
+		Remediation: "Remove get/list/watch on secrets, or limit it with resourceNames to the secrets the subject needs.",
+		check: func(rule rbacv1.PolicyRule, namespaced bool) (string, string, bool) {
+			if ruleAllows(rule, []string{"get", "list", "watch"}, "", []string{"secrets"}) {
+				return "can read secrets", "", true
+			}
+			return "", "", false
+		},
+	},
+}
+
+// apiGroupNames, API gruplarını virgülle listeler; çekirdek grup ("") core olarak yazılır.
+func apiGroupNames(groups []string) string {
+	names := make([]string, len(groups))
+	for i, group := range groups {
+		names[i] = group
+		if group == "" {
+			names[i] = "core"
+		}
+	}
+	return strings.Join(names, ",")
+}
+
+// isFullWildcard, kuralın tüm API gruplarında tüm kaynaklara tüm fiillere izin verip vermediğini söyler.
+func isFullWildcard(rule rbacv1.PolicyRule) bool {
+	return slices.Contains(rule.Verbs, rbacv1.VerbAll) && slices.Contains(rule.Resources, rbacv1.ResourceAll) &&
+		slices.Contains(rule.APIGroups, rbacv1.APIGroupAll)
+}
+
+// isRBACCheck, ruleId'nin bir RBAC kontrolüne ait olup olmadığını söyler.
+func isRBACCheck(id string) bool {
+	return slices.ContainsFunc(rbacChecks, func(c rbacCheck) bool { return c.ID == id })
+}
+
+// lowerSeverity, önem derecesini bir seviye düşürür.
+func lowerSeverity(severity string) string {
+	if rank := severityRank(severity); rank > 0 {
+		return severities[rank-1]
+	}
+	return severity
+}
+
+// analyzeRBAC, her grant'ın kurallarında yükseltme yollarını arar. Bulgular
+// binding'in subject'ine bağlanır (örn. clusterrolebinding/ops subjects[1]);
+// böylece aynı rolün yeni bir subject'e verilmesi baseline'da bastırılmaz. Bir
+// kontrol rolün birden fazla kuralında eşleşirse tek bulgu en yüksek önemle
+// bildirilir. Namespace ile sınırlı grant'lar ve resourceNames ile adı verilen
+// nesnelere sınırlı kurallar birer seviye düşük önemlidir. Cluster-admin eşdeğeri
+// bir kural yalnızca rbac-wildcard olarak bildirilir.
+func analyzeRBAC(grants []rbacGrant) []Finding {
+	var findings []Finding
+	for _, g := range grants {
+		for _, c := range rbacChecks {
+			var best Finding
+			var matched []string
+			for i, rule := range g.Rules {
+				// Her şeye izin veren kuralın diğer yolları ayrıca bildirilmez
+				if isFullWildcard(rule) && c.ID != "rbac-wildcard" {
+					continue
+				}
+				text, severity, ok := c.check(rule, g.Scope != "")
+				if !ok {
+					continue
+				}
+				if severity == "" {
+					severity = c.Severity
+				}
+				if g.Scope != "" {
+					severity = lowerSeverity(severity)
+				}
+				if len(rule.ResourceNames) > 0 {
+					severity = lowerSeverity(severity)
+					text += " (only " + strings.Join(rule.ResourceNames, ", ") + ")"
+				}
+				matched = append(matched, fmt.Sprintf("rules[%d]", i))
+				if best.RuleID == "" || severityRank(severity) > severityRank(best.Severity) {
+					best = Finding{RuleID: c.ID, Severity: severity, Description: text}
+				}
+			}
+			if len(matched) == 0 {
+				continue
+			}
+			best.Resource = g.BindingRef
+			best.FieldPath = fmt.Sprintf("subjects[%d]", g.SubjectIndex)
+			best.Namespace = g.Scope
+			best.Description = fmt.Sprintf("%s %s %s via %s → %s %s.", g.Subject, best.Description, g.scopeText(), g.Binding, g.Role, strings.Join(matched, ", "))
+			best.Remediation = c.Remediation
+			best.Source = SourceRule
+			findings = append(findings, best)
+		}
+	}
+	sortFindings(findings)
+	return findings
+}
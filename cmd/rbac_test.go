@@ -0,0 +1,338 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+)
+
+func TestRuleAllows(t *testing.T) {
+	tests := []struct {
+		name      string
+		rule      rbacv1.PolicyRule
+		verbs     []string
+		group     string
+		resources []string
+		want      bool
+	}{
+		{"exact match", rbacv1.PolicyRule{Verbs: []string{"get"}, APIGroups: []string{""}, Resources: []string{"secrets"}}, []string{"get", "list"}, "", []string{"secrets"}, true},
+		{"other verb", rbacv1.PolicyRule{Verbs: []string{"list"}, APIGroups: []string{""}, Resources: []string{"pods/exec"}}, []string{"create"}, "", []string{"pods/exec"}, false},
+		{"wildcard verb", rbacv1.PolicyRule{Verbs: []string{"*"}, APIGroups: []string{""}, Resources: []string{"secrets"}}, []string{"watch"}, "", []string{"secrets"}, true},
+		{"other group", rbacv1.PolicyRule{Verbs: []string{"get"}, APIGroups: []string{"apps"}, Resources: []string{"secrets"}}, []string{"get"}, "", []string{"secrets"}, false},
+		{"wildcard group", rbacv1.PolicyRule{Verbs: []string{"get"}, APIGroups: []string{"*"}, Resources: []string{"secrets"}}, []string{"get"}, "", []string{"secrets"}, true},
+		{"wildcard resource", rbacv1.PolicyRule{Verbs: []string{"get"}, APIGroups: []string{""}, Resources: []string{"*"}}, []string{"get"}, "", []string{"nodes/proxy"}, true},
+		{"subresource of every resource", rbacv1.PolicyRule{Verbs: []string{"create"}, APIGroups: []string{""}, Resources: []string{"*/exec"}}, []string{"create"}, "", []string{"pods/exec"}, true},
+		{"subresource wildcard does not match the parent", rbacv1.PolicyRule{Verbs: []string{"get"}, APIGroups: []string{""}, Resources: []string{"*/exec"}}, []string{"get"}, "", []string{"pods"}, false},
+		{"parent does not match the subresource", rbacv1.PolicyRule{Verbs: []string{"create"}, APIGroups: []string{""}, Resources: []string{"pods"}}, []string{"create"}, "", []string{"pods/exec"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ruleAllows(tt.rule, tt.verbs, tt.group, tt.resources); got != tt.want {
+				t.Errorf("ruleAllows = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAnalyzeRBAC(t *testing.T) {
+	tests := []struct {
+		name        string
+		manifest    string
+		want        []string
+		description string // bulgulardan birinin açıklamasında beklenen metin
+	}{
+		{
+			name: "role binding lowers severity",
+			manifest: `apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata: {name: ops}
+rules:
+- apiGroups: [""]
+  resources: [secrets]
+  verbs: [get]
+- apiGroups: [""]
+  resources: [pods/exec]
+  verbs: [create]
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRoleBinding
+metadata: {name: ops}
+roleRef: {apiGroup: rbac.authorization.k8s.io, kind: ClusterRole, name: ops}
+subjects:
+- {kind: User, name: alice}
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: RoleBinding
+metadata: {name: ops, namespace: ci}
+roleRef: {apiGroup: rbac.authorization.k8s.io, kind: ClusterRole, name: ops}
+subjects:
+- {kind: ServiceAccount, name: deployer}
+`,
+			want: []string{
+				"rbac-secrets-read high clusterrolebinding/ops subjects[0]",
+				"rbac-pods-exec high clusterrolebinding/ops subjects[0]",
+				"rbac-secrets-read medium rolebinding/ops subjects[0]",
+				"rbac-pods-exec medium rolebinding/ops subjects[0]",
+			},
+			description: "ServiceAccount ci/deployer can read secrets in namespace ci via RoleBinding ci/ops → clusterrole/ops rules[0].",
+		},
+		{
+			name: "resourceNames lowers severity",
+			manifest: `apiVersion: rbac.authorization.k8s.io/v1
+kind: Role
+metadata: {name: reader, namespace: app}
+rules:
+- apiGroups: [""]
+  resources: [secrets]
+  resourceNames: [db-password, api-key]
+  verbs: [get]
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: RoleBinding
+metadata: {name: reader, namespace: app}
+roleRef: {apiGroup: rbac.authorization.k8s.io, kind: Role, name: reader}
+subjects:
+- {kind: ServiceAccount, name: app}
+`,
+			want:        []string{"rbac-secrets-read low rolebinding/reader subjects[0]"},
+			description: "can read secrets (only db-password, api-key) in namespace app",
+		},
+		{
+			name: "cluster admin is only a wildcard finding",
+			manifest: `apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata: {name: root}
+rules:
+- apiGroups: ["*"]
+  resources: ["*"]
+  verbs: ["*"]
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRoleBinding
+metadata: {name: root}
+roleRef: {apiGroup: rbac.authorization.k8s.io, kind: ClusterRole, name: root}
+subjects:
+- {kind: Group, name: platform}
+`,
+			want:        []string{"rbac-wildcard critical clusterrolebinding/root subjects[0]"},
+			description: "Group platform has every verb on every resource cluster-wide",
+		},
+		{
+			name: "core group is named",
+			manifest: `apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata: {name: lister}
+rules:
+- apiGroups: ["", apps]
+  resources: ["*"]
+  verbs: [list]
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRoleBinding
+metadata: {name: lister}
+roleRef: {apiGroup: rbac.authorization.k8s.io, kind: ClusterRole, name: lister}
+subjects:
+- {kind: User, name: bob}
+`,
+			want: []string{
+				"rbac-wildcard high clusterrolebinding/lister subjects[0]",
+				"rbac-secrets-read high clusterrolebinding/lister subjects[0]",
+			},
+			description: "can list every resource in API groups core,apps",
+		},
+		{
+			name: "cluster-scoped paths are not reported for role bindings",
+			manifest: `apiVersion: rbac.authorization.k8s.io/v1
+kind: Role
+metadata: {name: binder, namespace: team}
+rules:
+- apiGroups: [rbac.authorization.k8s.io]
+  resources: [roles, clusterroles]
+  verbs: [bind]
+- apiGroups: [""]
+  resources: [nodes/proxy, users]
+  verbs: [get, impersonate]
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: RoleBinding
+metadata: {name: binder, namespace: team}
+roleRef: {apiGroup: rbac.authorization.k8s.io, kind: Role, name: binder}
+subjects:
+- {kind: User, name: carol}
+`,
+			want: []string{"rbac-escalate-bind high rolebinding/binder subjects[0]"},
+		},
+		{
+			name: "each subject is a separate finding",
+			manifest: `apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata: {name: reader}
+rules:
+- apiGroups: [""]
+  resources: [secrets]
+  resourceNames: [tls]
+  verbs: [get]
+- apiGroups: [""]
+  resources: [secrets]
+  verbs: [list]
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRoleBinding
+metadata: {name: readers}
+roleRef: {apiGroup: rbac.authorization.k8s.io, kind: ClusterRole, name: reader}
+subjects:
+- {kind: User, name: alice}
+- {kind: User, name: bob}
+`,
+			want: []string{
+				"rbac-secrets-read high clusterrolebinding/readers subjects[0]",
+				"rbac-secrets-read high clusterrolebinding/readers subjects[1]",
+			},
+			description: "User bob can read secrets cluster-wide via ClusterRoleBinding readers → clusterrole/reader rules[0], rules[1].",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			quietStatus(t)
+			grants := effectiveGrants(rbacFromManifests(manifestObjects(tt.manifest, "test.yaml")), false)
+			findings := analyzeRBAC(grants)
+			assertFindings(t, findings, tt.want)
+			if tt.description == "" {
+				return
+			}
+			found := false
+			for _, f := range findings {
+				found = found || strings.Contains(f.Description, tt.description)
+			}
+			if !found {
+				t.Errorf("no finding description contains %q", tt.description)
+			}
+		})
+	}
+}
+
+func TestEffectiveGrants(t *testing.T) {
+	quietStatus(t)
+	in := rbacFromManifests(manifestObjects(`apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRoleBinding
+metadata: {name: "system:node-proxier"}
+roleRef: {apiGroup: rbac.authorization.k8s.io, kind: ClusterRole, name: "system:node-proxier"}
+subjects:
+- {kind: User, name: "system:kube-proxy"}
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRoleBinding
+metadata:
+  name: cluster-admin
+  labels: {kubernetes.io/bootstrapping: rbac-defaults}
+roleRef: {apiGroup: rbac.authorization.k8s.io, kind: ClusterRole, name: cluster-admin}
+subjects:
+- {kind: Group, name: "system:masters"}
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRoleBinding
+metadata: {name: admins}
+roleRef: {apiGroup: rbac.authorization.k8s.io, kind: ClusterRole, name: cluster-admin}
+subjects:
+- {kind: Group, name: "system:authenticated"}
+- {kind: User, name: dave}
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: RoleBinding
+metadata: {name: dev, namespace: web}
+roleRef: {apiGroup: rbac.authorization.k8s.io, kind: Role, name: dev}
+subjects:
+- {kind: ServiceAccount, name: builder, namespace: ci}
+`, "test.yaml"))
+
+	grants := effectiveGrants(in, false)
+	var got []string
+	for _, g := range grants {
+		got = append(got, fmt.Sprintf("%s %s subjects[%d] %s", g.Subject, g.BindingRef, g.SubjectIndex, g.Role))
+		if !g.Missing {
+			t.Errorf("grant %s %s resolved a role that is not in the input", g.Subject, g.Role)
+		}
+	}
+	want := []string{
+		"Group system:authenticated clusterrolebinding/admins subjects[0] clusterrole/cluster-admin",
+		"ServiceAccount ci/builder rolebinding/dev subjects[0] role/dev",
+		"User dave clusterrolebinding/admins subjects[1] clusterrole/cluster-admin",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("grants:\n  got  %q\n  want %q", got, want)
+	}
+	if all := effectiveGrants(in, true); len(all) != 5 {
+		t.Errorf("with system grants: %d grants, want 5", len(all))
+	}
+}
+
+func TestAnalyzeRBACBuiltinGroups(t *testing.T) {
+	quietStatus(t)
+	in := rbacFromManifests(manifestObjects(`apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata: {name: admin-all}
+rules:
+- apiGroups: ["*"]
+  resources: ["*"]
+  verbs: ["*"]
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRoleBinding
+metadata: {name: admins}
+roleRef: {apiGroup: rbac.authorization.k8s.io, kind: ClusterRole, name: admin-all}
+subjects:
+- {kind: Group, name: "system:authenticated"}
+- {kind: User, name: "system:anonymous"}
+`, "test.yaml"))
+	assertFindings(t, analyzeRBAC(effectiveGrants(in, false)), []string{
+		"rbac-wildcard critical clusterrolebinding/admins subjects[0]",
+		"rbac-wildcard critical clusterrolebinding/admins subjects[1]",
+	})
+}
+
+func TestRBACBaselineKeepsNewSubjects(t *testing.T) {
+	quietStatus(t)
+	manifest := `apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata: {name: ops}
+rules:
+- apiGroups: [""]
+  resources: [secrets]
+  verbs: [get]
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRoleBinding
+metadata: {name: ops-alice}
+roleRef: {apiGroup: rbac.authorization.k8s.io, kind: ClusterRole, name: ops}
+subjects:
+- {kind: User, name: alice}
+`
+	accepted := analyzeRBAC(effectiveGrants(rbacFromManifests(manifestObjects(manifest, "test.yaml")), false))
+	if len(accepted) != 1 {
+		t.Fatalf("got %d findings, want 1", len(accepted))
+	}
+	baseline := Suppression{RuleID: accepted[0].RuleID, Resource: accepted[0].Resource, Namespace: accepted[0].Namespace,
+		FieldPath: accepted[0].FieldPath, Justification: "accepted"}
+
+	// Aynı rol yeni bir binding ile başka bir kullanıcıya verilir
+	manifest += `---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRoleBinding
+metadata: {name: ops-mallory}
+roleRef: {apiGroup: rbac.authorization.k8s.io, kind: ClusterRole, name: ops}
+subjects:
+- {kind: User, name: mallory}
+`
+	var reported []string
+	for _, f := range analyzeRBAC(effectiveGrants(rbacFromManifests(manifestObjects(manifest, "test.yaml")), false)) {
+		if !baseline.matches(f) {
+			reported = append(reported, f.Description)
+		}
+	}
+	if len(reported) != 1 || !strings.HasPrefix(reported[0], "User mallory ") {
+		t.Errorf("findings not covered by the baseline = %q, want only mallory's", reported)
+	}
+}
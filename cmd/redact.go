@@ -0,0 +1,361 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"sigs.k8s.io/yaml"
+)
+
+// Gizli değerlerin yerine yazılan metin; modelin değerin varlığını görmesi yeterlidir.
+const redactedFormat = "<redacted:%s>"
+
+const lastAppliedAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+
+// RedactPattern, metin içindeki gizli değerleri bulan bir kuraldır. Regex'te
+// "secret" adlı bir grup varsa yalnızca o kısım, yoksa eşleşmenin tamamı maskelenir.
+type RedactPattern struct {
+	Name  string `json:"name"`
+	Regex string `json:"regex"`
+}
+
+// RedactConfig, ~/.config/kube-ai/redact.yaml veya KUBE_AI_REDACT ile verilen
+// ek kurallardır; dahili kuralların yerine geçmez, onlara eklenir:
+//
+//	keys:
+//	  - "(?i)^x-internal-"
+//	patterns:
+//	  - name: internal-token
+//	    regex: "itk_[A-Za-z0-9]{32}"
+type RedactConfig struct {
+	Keys     []string        `json:"keys"`
+	Patterns []RedactPattern `json:"patterns"`
+}
+
+// Değeri her zaman maskelenen alan ve env değişkeni adları.
+var defaultRedactKeys = []string{
+	`(?i)(password|passwd|pwd|secret|token|api[_-]?key|access[_-]?key|private[_-]?key|credentials?)$`,
+	`(?i)^(client-key-data|client-certificate-data|certificate-authority-data)$`,
+}
+
+// Serbest metinde (loglar, describe çıktıları, dosyalar) aranan gizli değerler.
+var defaultRedactPatterns = []RedactPattern{
+	{Name: "pem-block", Regex: `-----BEGIN [A-Z0-9 ]+-----[\s\S]*?-----END [A-Z0-9 ]+-----`},
+	{Name: "bearer-token", Regex: `(?i)\bbearer\s+(?P<secret>[A-Za-z0-9\-._~+/]+=*)`},
+	{Name: "jwt", Regex: `\beyJ[A-Za-z0-9_-]{8,}\.[A-Za-z0-9_-]{8,}\.[A-Za-z0-9_-]{8,}`},
+	{Name: "url-credentials", Regex: `://[^:/\s@]+:(?P<secret>[^@\s/]+)@`},
+	{Name: "aws-access-key", Regex: `\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`},
+	{Name: "secret-arg", Regex: `(?i)--?[\w-]*(?:password|token|secret)[\w-]*[= ](?P<secret>[^\s"',]+)`},
+	{Name: "sensitive-key", Regex: `(?i)\b[\w.-]*(?:password|passwd|secret|token|api[_-]?key|access[_-]?key|private[_-]?key)["']?[ \t]*[:=][ \t]*["']?(?P<secret>[^\s"'{}\[\]|>,][^\s"',]*)`},
+}
+
+// Redaction, maskelenen bir değerin kaydıdır. Değerin kendisi saklanmaz.
+type Redaction struct {
+	Rule     string
+	Location string
+}
+
+type redactPattern struct {
+	name string
+	re   *regexp.Regexp
+}
+
+// Redactor, modele gönderilecek verideki Secret içeriklerini, hassas env
+// değerlerini, token'ları ve sertifikaları maskeler.
+type Redactor struct {
+	keys     []*regexp.Regexp
+	patterns []redactPattern
+
+	mu    sync.Mutex
+	found []Redaction
+}
+
+var (
+	redactorOnce sync.Once
+	redactorInst *Redactor
+	// profileRedact, aktif config profilindeki ek kurallardır.
+	profileRedact RedactConfig
+)
+
+// redactor, dahili ve kullanıcı kurallarıyla oluşturulmuş ortak Redactor'ı döndürür.
+func redactor() *Redactor {
+	redactorOnce.Do(func() {
+		redactorInst = newRedactor(redactRules())
+	})
+	return redactorInst
+}
+
+// redactRules, dahili kuralları profildeki ve redact.yaml'daki kurallarla birleştirir.
+func redactRules() RedactConfig {
+	cfg := RedactConfig{
+		Keys:     append(append([]string(nil), defaultRedactKeys...), profileRedact.Keys...),
+		Patterns: append(append([]RedactPattern(nil), defaultRedactPatterns...), profileRedact.Patterns...),
+	}
+	if path := redactFile(); path != "" {
+		if data, err := os.ReadFile(path); err == nil {
+			var user RedactConfig
+			if err := yaml.Unmarshal(data, &user); err != nil {
+				fmt.Fprintf(statusOut, "⚠️ Warning: could not parse redaction rules %s: %v\n", path, err)
+			} else {
+				cfg.Keys = append(cfg.Keys, user.Keys...)
+				cfg.Patterns = append(cfg.Patterns, user.Patterns...)
+			}
+		}
+	}
+	return cfg
+}
+
+// redactFile, kullanıcı redaction kurallarının yolunu döndürür.
+func redactFile() string {
+	if path := os.Getenv("KUBE_AI_REDACT"); path != "" {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".config", "kube-ai", "redact.yaml")
+}
+
+// newRedactor, kuralları derler. Geçersiz bir kural uyarıyla atlanır.
+func newRedactor(cfg RedactConfig) *Redactor {
+	r := &Redactor{}
+	for _, key := range cfg.Keys {
+		re, err := regexp.Compile(key)
+		if err != nil {
+			fmt.Fprintf(statusOut, "⚠️ Warning: invalid redaction key rule %q: %v\n", key, err)
+			continue
+		}
+		r.keys = append(r.keys, re)
+	}
+	for _, p := range cfg.Patterns {
+		re, err := regexp.Compile(p.Regex)
+		if err != nil {
+			fmt.Fprintf(statusOut, "⚠️ Warning: invalid redaction pattern %q: %v\n", p.Name, err)
+			continue
+		}
+		name := p.Name
+		if name == "" {
+			name = "custom"
+		}
+		r.patterns = append(r.patterns, redactPattern{name: name, re: re})
+	}
+	return r
+}
+
+func (r *Redactor) record(rule, location string) string {
+	r.mu.Lock()
+	r.found = append(r.found, Redaction{Rule: rule, Location: location})
+	r.mu.Unlock()
+	return fmt.Sprintf(redactedFormat, rule)
+}
+
+func (r *Redactor) sensitiveKey(key string) bool {
+	for _, re := range r.keys {
+		if re.MatchString(key) {
+			return true
+		}
+	}
+	return false
+}
+
+// Object, bir Kubernetes nesnesini (veya listesini) yerinde maskeler: Secret
+// data/stringData değerleri, adı hassas alan ve env değerleri ile metin
+// kurallarına uyan tüm string'ler.
+func (r *Redactor) Object(obj map[string]interface{}, location string) {
+	r.walk(obj, location, "")
+}
+
+func (r *Redactor) walk(v interface{}, location, path string) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		isSecret := v["kind"] == "Secret"
+		envName, _ := v["name"].(string)
+		for key, value := range v {
+			fieldPath := joinFieldPath(path, key)
+			switch s, isString := value.(string); {
+			case isString && isRedacted(s):
+			case isSecret && (key == "data" || key == "stringData"):
+				if data, ok := value.(map[string]interface{}); ok {
+					for name, item := range data {
+						if s, ok := item.(string); ok && !isRedacted(s) {
+							data[name] = r.record("secret-data", location+": "+joinFieldPath(fieldPath, name))
+						}
+					}
+				}
+			case isString && s != "" && r.sensitiveKey(key):
+				v[key] = r.record("sensitive-key", location+": "+fieldPath)
+			case isString && s != "" && key == "value" && envName != "" && r.sensitiveKey(envName):
+				v[key] = r.record("sensitive-env", location+": "+fieldPath)
+			case isString && key == lastAppliedAnnotation:
+				v[key] = r.embeddedJSON(s, location, fieldPath)
+			default:
+				v[key] = r.walk(value, location, fieldPath)
+			}
+		}
+		return v
+	case []interface{}:
+		for i := range v {
+			v[i] = r.walk(v[i], location, fmt.Sprintf("%s[%d]", path, i))
+		}
+		return v
+	case string:
+		return r.patternsIn(v, location+": "+path, false)
+	}
+	return v
+}
+
+// embeddedJSON, last-applied-configuration gibi JSON içeren string alanları maskeler.
+func (r *Redactor) embeddedJSON(s, location, path string) string {
+	var embedded interface{}
+	if err := json.Unmarshal([]byte(s), &embedded); err != nil {
+		return r.patternsIn(s, location+": "+path, false)
+	}
+	embedded = r.walk(embedded, location, path)
+	out, err := marshalJSON(embedded, "")
+	if err != nil {
+		return fmt.Sprintf(redactedFormat, "unparsable")
+	}
+	return out
+}
+
+// marshalJSON, maskeleme metnindeki < ve > karakterlerini kaçışlamadan JSON üretir.
+func marshalJSON(v interface{}, indent string) (string, error) {
+	var b strings.Builder
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", indent)
+	if err := enc.Encode(v); err != nil {
+		return "", err
+	}
+	return strings.TrimRight(b.String(), "\n"), nil
+}
+
+// Text, dosya içeriği, log veya describe çıktısı gibi serbest metni maskeler.
+// Kubernetes manifest'leri (YAML veya JSON) nesne olarak işlenir; yalnızca
+// bir değer maskelendiyse yeniden yazılır, aksi halde biçim korunur.
+func (r *Redactor) Text(text, location string) string {
+	trimmed := strings.TrimSpace(text)
+	if strings.HasPrefix(trimmed, "{") {
+		var obj map[string]interface{}
+		if err := json.Unmarshal([]byte(trimmed), &obj); err == nil {
+			before := r.count()
+			r.Object(obj, location)
+			if r.count() > before {
+				if out, err := marshalJSON(obj, "  "); err == nil {
+					return out
+				}
+			}
+			return text
+		}
+	}
+
+	text = r.patternsIn(text, location, true)
+	docs := yamlDocSeparator.Split(text, -1)
+	if len(docs) > 1 || strings.Contains(text, "kind:") {
+		for i, doc := range docs {
+			var obj map[string]interface{}
+			if err := yaml.Unmarshal([]byte(doc), &obj); err != nil || (obj["kind"] == nil && obj["apiVersion"] == nil) {
+				continue
+			}
+			before := r.count()
+			r.Object(obj, location)
+			if r.count() > before {
+				if out, err := yaml.Marshal(obj); err == nil {
+					docs[i] = string(out)
+				}
+			}
+		}
+		text = strings.Join(docs, "---\n")
+	}
+	return text
+}
+
+var yamlDocSeparator = regexp.MustCompile(`(?m)^---[ \t]*\n`)
+
+// patternsIn, metin kurallarını uygular. lines true ise konum satır numarasıyla kaydedilir.
+func (r *Redactor) patternsIn(text, location string, lines bool) string {
+	for _, p := range r.patterns {
+		matches := p.re.FindAllStringSubmatchIndex(text, -1)
+		if len(matches) == 0 {
+			continue
+		}
+		group := p.re.SubexpIndex("secret")
+
+		var b strings.Builder
+		last := 0
+		for _, m := range matches {
+			start, end := m[0], m[1]
+			if group > 0 && m[2*group] >= 0 {
+				start, end = m[2*group], m[2*group+1]
+			}
+			if isRedacted(text[start:end]) || plainScalars[strings.ToLower(text[start:end])] {
+				continue
+			}
+			where := location
+			if lines {
+				where = fmt.Sprintf("%s:%d", location, strings.Count(text[:start], "\n")+1)
+			}
+			b.WriteString(text[last:start])
+			b.WriteString(r.record(p.name, where))
+			last = end
+		}
+		b.WriteString(text[last:])
+		text = b.String()
+	}
+	return text
+}
+
+// Anahtar adı hassas görünse de gizli olmayan değerler (ör. automountServiceAccountToken: false).
+var plainScalars = map[string]bool{"true": true, "false": true, "null": true, "~": true}
+
+func isRedacted(s string) bool {
+	return strings.HasPrefix(s, "<redacted:")
+}
+
+func (r *Redactor) count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.found)
+}
+
+// Redactions, şimdiye kadar maskelenen değerleri konuma göre sıralı döndürür.
+func (r *Redactor) Redactions() []Redaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	found := append([]Redaction(nil), r.found...)
+	sort.SliceStable(found, func(i, j int) bool { return found[i].Location < found[j].Location })
+	return found
+}
+
+func joinFieldPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// printRedactions, maskelenen değerlerin sayısını, --show-redacted verilmişse
+// hangi kuralla nerede maskelendiklerini yazdırır. Değerlerin kendisi yazdırılmaz.
+func printRedactions() {
+	found := redactor().Redactions()
+	if len(found) == 0 {
+		return
+	}
+	if !ShowRedacted {
+		if Verbose {
+			fmt.Fprintf(statusOut, "\n🔒 Redacted %d sensitive value(s) before sending (use --show-redacted for details)\n", len(found))
+		}
+		return
+	}
+	fmt.Fprintf(statusOut, "\n🔒 Redacted %d sensitive value(s) before sending:\n", len(found))
+	for _, f := range found {
+		fmt.Fprintf(statusOut, "   - %-16s %s\n", f.Rule, f.Location)
+	}
+}
@@ -0,0 +1,259 @@
+package cmd
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// findingFormats, audit'in desteklediği çıktı biçimleridir.
+var findingFormats = []string{"table", "json", "sarif", "junit"}
+
+// writeFindings, bulguları seçilen biçimde yazar.
+func writeFindings(out io.Writer, format string, findings []Finding) error {
+	switch format {
+	case "json":
+		return writeFindingsJSON(out, findings)
+	case "sarif":
+		return writeFindingsSARIF(out, findings)
+	case "junit":
+		return writeFindingsJUnit(out, findings)
+	default:
+		writeFindingsTable(out, findings)
+		return nil
+	}
+}
+
+// SARIF 2.1.0'ın kullanılan alt kümesi.
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string         `json:"id"`
+	ShortDescription sarifMessage   `json:"shortDescription"`
+	Help             sarifMessage   `json:"help"`
+	Properties       map[string]any `json:"properties"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID     string          `json:"ruleId"`
+	Level      string          `json:"level"`
+	Message    sarifMessage    `json:"message"`
+	Locations  []sarifLocation `json:"locations"`
+	Properties map[string]any  `json:"properties"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation *sarifPhysicalLocation `json:"physicalLocation,omitempty"`
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+type sarifLogicalLocation struct {
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+	Kind               string `json:"kind"`
+}
+
+// sarifLevel, önem derecesini SARIF seviyesine çevirir.
+func sarifLevel(severity string) string {
+	switch severity {
+	case "critical", "high":
+		return "error"
+	case "medium":
+		return "warning"
+	default:
+		return "note"
+	}
+}
+
+// sarifText, bulgunun SARIF mesajıdır.
+func sarifText(f Finding) string {
+	text := fmt.Sprintf("%s: %s", f.Resource, f.Description)
+	if f.Explanation != "" {
+		text += " " + f.Explanation
+	}
+	return text + " Remediation: " + f.Remediation
+}
+
+// securitySeverity, GitHub code scanning'in önem sıralaması için kullandığı puandır.
+var securitySeverity = map[string]string{
+	"critical": "9.5",
+	"high":     "8.0",
+	"medium":   "5.5",
+	"low":      "3.0",
+	"info":     "0.0",
+}
+
+// writeFindingsSARIF, bulguları SARIF 2.1.0 olarak yazar.
+func writeFindingsSARIF(out io.Writer, findings []Finding) error {
+	run := sarifRun{
+		Tool: sarifTool{Driver: sarifDriver{
+			Name:           "kube-ai",
+			Version:        VERSION,
+			InformationURI: "https://github.com/rmysatay/kube-ai",
+			Rules:          []sarifRule{},
+		}},
+		Results: []sarifResult{},
+	}
+
+	rules := map[string]bool{}
+	for _, f := range findings {
+		// Kurallar ilk (en yüksek önemli) bulgularından tanımlanır
+		if !rules[f.RuleID] {
+			rules[f.RuleID] = true
+			run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule{
+				ID:               f.RuleID,
+				ShortDescription: sarifMessage{Text: f.RuleID},
+				Help:             sarifMessage{Text: f.Remediation},
+				Properties: map[string]any{
+					"tags":              []string{"security", "kubernetes"},
+					"security-severity": securitySeverity[f.Severity],
+				},
+			})
+		}
+
+		name := f.Resource
+		if f.FieldPath != "" {
+			name += "." + f.FieldPath
+		}
+		location := sarifLocation{
+			LogicalLocations: []sarifLogicalLocation{{FullyQualifiedName: name, Kind: "resource"}},
+		}
+		if f.File != "" {
+			location.PhysicalLocation = &sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: f.File}}
+			if f.Line > 0 {
+				location.PhysicalLocation.Region = &sarifRegion{StartLine: f.Line}
+			}
+		}
+		run.Results = append(run.Results, sarifResult{
+			RuleID:    f.RuleID,
+			Level:     sarifLevel(f.Severity),
+			Message:   sarifMessage{Text: sarifText(f)},
+			Locations: []sarifLocation{location},
+			Properties: map[string]any{
+				"severity":  f.Severity,
+				"resource":  f.Resource,
+				"fieldPath": f.FieldPath,
+				"source":    f.Source,
+			},
+		})
+	}
+
+	enc := json.NewEncoder(out)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	return enc.Encode(sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []sarifRun{run},
+	})
+}
+
+// JUnit XML'in CI test raporlayıcılarının okuduğu alt kümesi.
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	File      string        `xml:"file,attr,omitempty"`
+	Line      int           `xml:"line,attr,omitempty"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",cdata"`
+}
+
+// writeFindingsJUnit, her bulguyu başarısız bir test olarak JUnit XML biçiminde yazar.
+// Test sınıfı kaynaktır; böylece raporlayıcılar bulguları kaynağa göre gruplar.
+func writeFindingsJUnit(out io.Writer, findings []Finding) error {
+	suite := junitTestSuite{Name: "kube-ai audit", Tests: len(findings), Failures: len(findings)}
+	for _, f := range findings {
+		var text strings.Builder
+		fmt.Fprintf(&text, "Severity: %s\nResource: %s\n", f.Severity, f.Resource)
+		if f.FieldPath != "" {
+			fmt.Fprintf(&text, "Field: %s\n", f.FieldPath)
+		}
+		if f.File != "" {
+			fmt.Fprintf(&text, "Location: %s:%d\n", f.File, f.Line)
+		}
+		fmt.Fprintf(&text, "Source: %s\nRemediation: %s\n", f.Source, f.Remediation)
+		if f.Explanation != "" {
+			fmt.Fprintf(&text, "Explanation: %s\n", f.Explanation)
+		}
+
+		suite.TestCases = append(suite.TestCases, junitTestCase{
+			Name:      fmt.Sprintf("%s [%s]", f.RuleID, f.Severity),
+			ClassName: f.Resource,
+			File:      f.File,
+			Line:      f.Line,
+			Failure:   &junitFailure{Message: f.Description, Type: f.Severity, Text: text.String()},
+		})
+	}
+
+	fmt.Fprint(out, xml.Header)
+	enc := xml.NewEncoder(out)
+	enc.Indent("", "  ")
+	if err := enc.Encode(junitTestSuites{
+		Name:     "kube-ai",
+		Tests:    suite.Tests,
+		Failures: suite.Failures,
+		Suites:   []junitTestSuite{suite},
+	}); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintln(out)
+	return err
+}
@@ -0,0 +1,184 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+const reportManifest = `apiVersion: v1
+kind: Pod
+metadata:
+  name: web
+spec:
+  automountServiceAccountToken: false
+  hostNetwork: true
+  securityContext: {runAsNonRoot: true}
+  containers:
+  - name: web
+    image: nginx:1.27
+    securityContext:
+      privileged: true
+    resources:
+      limits: {cpu: 500m, memory: 256Mi}
+    livenessProbe: {httpGet: {path: /, port: 80}}
+    readinessProbe: {httpGet: {path: /, port: 80}}
+`
+
+// reportFindings, reportManifest'in dahili kural bulgularını dosya satırlarıyla döndürür.
+func reportFindings(t *testing.T) []Finding {
+	t.Helper()
+	quietStatus(t)
+	findings := runRules(manifestObjects(reportManifest, "pod.yaml"))
+	locateFindings(findings, "deploy/pod.yaml", reportManifest)
+	return findings
+}
+
+func TestWriteFindingsSARIF(t *testing.T) {
+	findings := append(reportFindings(t),
+		Finding{RuleID: "image-pull-policy", Severity: "low", Resource: "pod/web", Source: SourceAI},
+		Finding{RuleID: "team-label", Severity: "info", Resource: "pod/web", Source: SourcePolicy},
+	)
+	var out bytes.Buffer
+	if err := writeFindingsSARIF(&out, findings); err != nil {
+		t.Fatal(err)
+	}
+	var log sarifLog
+	if err := json.Unmarshal(out.Bytes(), &log); err != nil {
+		t.Fatalf("invalid SARIF JSON: %v\n%s", err, out.String())
+	}
+	if log.Version != "2.1.0" || !strings.Contains(log.Schema, "sarif-2.1.0") {
+		t.Errorf("version %q, schema %q", log.Version, log.Schema)
+	}
+	if len(log.Runs) != 1 || log.Runs[0].Tool.Driver.Name != "kube-ai" {
+		t.Fatalf("runs = %+v", log.Runs)
+	}
+
+	tests := []struct {
+		ruleID    string
+		level     string
+		uri       string
+		startLine int // 0: fiziksel konum yok ya da satır bulunamadı
+	}{
+		{"privileged-container", "error", "deploy/pod.yaml", 13},
+		{"host-network", "error", "deploy/pod.yaml", 7},
+		{"image-pull-policy", "note", "", 0},
+		{"team-label", "note", "", 0},
+	}
+	results := map[string]sarifResult{}
+	for _, r := range log.Runs[0].Results {
+		results[r.RuleID] = r
+	}
+	for _, tt := range tests {
+		t.Run(tt.ruleID, func(t *testing.T) {
+			r, ok := results[tt.ruleID]
+			if !ok {
+				t.Fatalf("no result for %s in %v", tt.ruleID, results)
+			}
+			if r.Level != tt.level {
+				t.Errorf("level = %q, want %q", r.Level, tt.level)
+			}
+			physical := r.Locations[0].PhysicalLocation
+			if tt.uri == "" {
+				if physical != nil {
+					t.Errorf("unexpected physical location %+v", physical)
+				}
+				return
+			}
+			if physical == nil || physical.ArtifactLocation.URI != tt.uri {
+				t.Fatalf("physical location = %+v, want %s", physical, tt.uri)
+			}
+			if physical.Region == nil || physical.Region.StartLine != tt.startLine {
+				t.Errorf("region = %+v, want startLine %d", physical.Region, tt.startLine)
+			}
+		})
+	}
+
+	// Her ruleId bir kez tanımlanır
+	seen := map[string]bool{}
+	for _, rule := range log.Runs[0].Tool.Driver.Rules {
+		if seen[rule.ID] {
+			t.Errorf("rule %s is defined twice", rule.ID)
+		}
+		seen[rule.ID] = true
+	}
+	if len(seen) != len(results) {
+		t.Errorf("%d rules for %d distinct results", len(seen), len(results))
+	}
+}
+
+func TestSARIFLevel(t *testing.T) {
+	for severity, want := range map[string]string{
+		"critical": "error",
+		"high":     "error",
+		"medium":   "warning",
+		"low":      "note",
+		"info":     "note",
+	} {
+		if got := sarifLevel(severity); got != want {
+			t.Errorf("sarifLevel(%s) = %s, want %s", severity, got, want)
+		}
+	}
+}
+
+func TestWriteFindingsJUnit(t *testing.T) {
+	findings := reportFindings(t)
+	var out bytes.Buffer
+	if err := writeFindingsJUnit(&out, findings); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(out.String(), xml.Header) {
+		t.Errorf("missing XML header:\n%s", out.String())
+	}
+	var suites junitTestSuites
+	if err := xml.Unmarshal(out.Bytes(), &suites); err != nil {
+		t.Fatalf("invalid JUnit XML: %v\n%s", err, out.String())
+	}
+	if suites.Tests != len(findings) || suites.Failures != len(findings) || len(suites.Suites) != 1 {
+		t.Fatalf("tests=%d failures=%d suites=%d, want %d findings in one suite", suites.Tests, suites.Failures, len(suites.Suites), len(findings))
+	}
+	suite := suites.Suites[0]
+	if suite.Failures != len(findings) || len(suite.TestCases) != len(findings) {
+		t.Errorf("suite failures=%d cases=%d, want %d", suite.Failures, len(suite.TestCases), len(findings))
+	}
+	for _, c := range suite.TestCases {
+		if c.Failure == nil || c.ClassName != "pod/web" || c.File != "deploy/pod.yaml" || c.Line == 0 {
+			t.Errorf("test case %+v", c)
+		}
+	}
+}
+
+func TestFailOnFindings(t *testing.T) {
+	findings := reportFindings(t)
+	tests := []struct {
+		failOn string
+		count  int // 0: hata beklenmez
+	}{
+		{"", 0},
+		{"critical", 1},
+		{"high", 2},
+		{"HIGH", 2},
+	}
+	previous := auditFailOn
+	t.Cleanup(func() { auditFailOn = previous })
+	for _, tt := range tests {
+		t.Run("fail-on "+tt.failOn, func(t *testing.T) {
+			auditFailOn = tt.failOn
+			err := failOnFindings(findings)
+			if tt.count == 0 {
+				if err != nil {
+					t.Errorf("err = %v, want none", err)
+				}
+				return
+			}
+			if ExitCode(err) != ExitFindings || ExitFindings != 5 {
+				t.Fatalf("exit code = %d, want 5 (err %v)", ExitCode(err), err)
+			}
+			if fe, ok := err.(*FindingsError); !ok || fe.Count != tt.count {
+				t.Errorf("err = %#v, want %d findings", err, tt.count)
+			}
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	openai "github.com/sashabaranov/go-openai"
+)
+
+const (
+	retryBaseDelay = time.Second
+	retryMaxDelay  = 30 * time.Second
+)
+
+// retryingDoer, 429 ve geçici 5xx yanıtlarını ve ağ hatalarını üstel bekleme
+// ile yeniden dener. Sunucu Retry-After gönderirse o süre kadar beklenir.
+// Kota tükendiğinde (insufficient_quota) tekrar denemenin anlamı olmadığı
+// için yanıt doğrudan döndürülür.
+type retryingDoer struct {
+	client     *http.Client
+	maxRetries int
+}
+
+// newHTTPClient, provider'ların kullandığı yeniden denemeli HTTP istemcisini döndürür.
+func newHTTPClient() openai.HTTPDoer {
+	return &retryingDoer{client: &http.Client{}, maxRetries: MaxRetries}
+}
+
+func (d *retryingDoer) Do(req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = body
+		}
+
+		resp, err := d.client.Do(req)
+		if attempt >= d.maxRetries || !shouldRetry(resp, err) || req.Context().Err() != nil {
+			return resp, err
+		}
+
+		delay := backoff(attempt, resp)
+		if resp != nil {
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		if Verbose {
+			reason := "network error"
+			if resp != nil {
+				reason = resp.Status
+			}
+			fmt.Fprintf(statusOut, "⏳ AI request failed (%s), retrying in %s (%d/%d)\n", reason, delay.Round(time.Millisecond), attempt+1, d.maxRetries)
+		}
+
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(delay):
+		}
+	}
+}
+
+// shouldRetry, yanıtın tekrar denenebilir olup olmadığını belirler.
+func shouldRetry(resp *http.Response, err error) bool {
+	if err != nil {
+		return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests:
+		return !isQuotaExhausted(resp)
+	case http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// isQuotaExhausted, 429 yanıtının rate limit değil kota bitişi olup olmadığını
+// kontrol eder. Gövde okunduktan sonra çağıran taraf için geri yerleştirilir.
+func isQuotaExhausted(resp *http.Response) bool {
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	return err == nil && strings.Contains(string(body), "insufficient_quota")
+}
+
+// backoff, bir sonraki denemeden önce beklenecek süreyi hesaplar.
+func backoff(attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if d, ok := retryAfter(resp.Header); ok {
+			if d > retryMaxDelay {
+				d = retryMaxDelay
+			}
+			return d
+		}
+	}
+
+	d := retryBaseDelay << uint(attempt)
+	if d > retryMaxDelay {
+		d = retryMaxDelay
+	}
+	// Aynı anda tekrar deneyen istemcileri dağıtmak için %0-25 jitter
+	return d + time.Duration(rand.Int63n(int64(d)/4+1))
+}
+
+// retryAfter, Retry-After (saniye veya HTTP tarihi) ve OpenAI'nin
+// retry-after-ms başlıklarını okur.
+func retryAfter(h http.Header) (time.Duration, bool) {
+	if ms := h.Get("Retry-After-Ms"); ms != "" {
+		if v, err := strconv.ParseFloat(ms, 64); err == nil && v >= 0 {
+			return time.Duration(v * float64(time.Millisecond)), true
+		}
+	}
+
+	value := h.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(value); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second, true
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		if d := time.Until(at); d > 0 {
+			return d, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
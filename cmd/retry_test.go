@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// scriptedServer, her istekte sıradaki yanıtı döndürür; son yanıt tekrarlanır.
+// Gövdenin her denemede yeniden gönderildiği de kontrol edilir.
+func scriptedServer(t *testing.T, responses []func(w http.ResponseWriter)) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := int(attempts.Add(1))
+		if body, _ := io.ReadAll(r.Body); string(body) != `{"model":"test"}` {
+			t.Errorf("attempt %d: body = %q", n, body)
+		}
+		responses[min(n, len(responses))-1](w)
+	}))
+	t.Cleanup(server.Close)
+	return server, &attempts
+}
+
+func respondWith(code int, headers map[string]string, body string) func(w http.ResponseWriter) {
+	return func(w http.ResponseWriter) {
+		for key, value := range headers {
+			w.Header().Set(key, value)
+		}
+		w.WriteHeader(code)
+		io.WriteString(w, body)
+	}
+}
+
+func TestRetryingDoer(t *testing.T) {
+	quota := `{"error":{"code":"insufficient_quota","message":"You exceeded your current quota"}}`
+	tests := []struct {
+		name         string
+		responses    []func(w http.ResponseWriter)
+		maxRetries   int
+		wantStatus   int
+		wantAttempts int32
+		wantBody     string
+	}{
+		{
+			name: "429 with Retry-After headers, then 200",
+			responses: []func(w http.ResponseWriter){
+				respondWith(http.StatusTooManyRequests, map[string]string{"Retry-After-Ms": "20"}, "slow down"),
+				respondWith(http.StatusTooManyRequests, map[string]string{"Retry-After": "0"}, "slow down"),
+				respondWith(http.StatusOK, nil, "ok"),
+			},
+			maxRetries:   3,
+			wantStatus:   http.StatusOK,
+			wantAttempts: 3,
+			wantBody:     "ok",
+		},
+		{
+			name:         "insufficient_quota is not retried",
+			responses:    []func(w http.ResponseWriter){respondWith(http.StatusTooManyRequests, map[string]string{"Retry-After": "0"}, quota)},
+			maxRetries:   3,
+			wantStatus:   http.StatusTooManyRequests,
+			wantAttempts: 1,
+			wantBody:     quota,
+		},
+		{
+			name:         "gives up after max retries",
+			responses:    []func(w http.ResponseWriter){respondWith(http.StatusServiceUnavailable, map[string]string{"Retry-After-Ms": "1"}, "down")},
+			maxRetries:   2,
+			wantStatus:   http.StatusServiceUnavailable,
+			wantAttempts: 3,
+			wantBody:     "down",
+		},
+		{
+			name:         "client errors are not retried",
+			responses:    []func(w http.ResponseWriter){respondWith(http.StatusBadRequest, map[string]string{"Retry-After": "0"}, "bad request")},
+			maxRetries:   3,
+			wantStatus:   http.StatusBadRequest,
+			wantAttempts: 1,
+			wantBody:     "bad request",
+		},
+		{
+			name:         "no retries configured",
+			responses:    []func(w http.ResponseWriter){respondWith(http.StatusTooManyRequests, map[string]string{"Retry-After": "0"}, "slow down")},
+			maxRetries:   0,
+			wantStatus:   http.StatusTooManyRequests,
+			wantAttempts: 1,
+			wantBody:     "slow down",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			quietStatus(t)
+			server, attempts := scriptedServer(t, tt.responses)
+			req, err := http.NewRequest(http.MethodPost, server.URL, strings.NewReader(`{"model":"test"}`))
+			if err != nil {
+				t.Fatal(err)
+			}
+			doer := &retryingDoer{client: server.Client(), maxRetries: tt.maxRetries}
+			resp, err := doer.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+			body, _ := io.ReadAll(resp.Body)
+			if resp.StatusCode != tt.wantStatus || string(body) != tt.wantBody {
+				t.Errorf("response = %d %q, want %d %q", resp.StatusCode, body, tt.wantStatus, tt.wantBody)
+			}
+			if got := attempts.Load(); got != tt.wantAttempts {
+				t.Errorf("attempts = %d, want %d", got, tt.wantAttempts)
+			}
+		})
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	header := func(key, value string) *http.Response {
+		return &http.Response{Header: http.Header{key: []string{value}}}
+	}
+	tests := []struct {
+		name     string
+		attempt  int
+		resp     *http.Response
+		min, max time.Duration
+	}{
+		{"first retry", 0, nil, retryBaseDelay, retryBaseDelay * 5 / 4},
+		{"exponential", 3, nil, 8 * retryBaseDelay, 10 * retryBaseDelay},
+		{"capped exponential", 20, nil, retryMaxDelay, retryMaxDelay * 5 / 4},
+		{"Retry-After seconds", 5, header("Retry-After", "2"), 2 * time.Second, 2 * time.Second},
+		{"Retry-After-Ms wins", 0, &http.Response{Header: http.Header{"Retry-After": {"5"}, "Retry-After-Ms": {"1500"}}}, 1500 * time.Millisecond, 1500 * time.Millisecond},
+		{"Retry-After is capped", 0, header("Retry-After", "3600"), retryMaxDelay, retryMaxDelay},
+		{"Retry-After-Ms is capped", 0, header("Retry-After-Ms", "600000"), retryMaxDelay, retryMaxDelay},
+		{"past HTTP date", 0, header("Retry-After", "Mon, 02 Jan 2006 15:04:05 GMT"), 0, 0},
+		{"invalid header falls back to exponential", 1, header("Retry-After", "soon"), 2 * retryBaseDelay, 2 * retryBaseDelay * 5 / 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := backoff(tt.attempt, tt.resp); got < tt.min || got > tt.max {
+				t.Errorf("backoff = %s, want between %s and %s", got, tt.min, tt.max)
+			}
+		})
+	}
+}
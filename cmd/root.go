@@ -1,6 +1,12 @@
 package cmd
 
 import (
+	"context"
+	"io"
+	"os"
+	"strings"
+	"time"
+
 	"github.com/spf13/cobra"
 )
 
@@ -10,22 +16,94 @@ var (
 	CountTokens   bool
 	Verbose       bool
 	MaxIterations int
+	ProviderName  string
+	BaseURL       string
+	APIVersion    string
+	NoStream      bool
+	Timeout       time.Duration
+	MaxRetries    int
+	Kubeconfig    string
+	KubeContext   string
+	KubeNamespace string
+	ShowRedacted  bool
+	Raw           bool
+	ContextSize   int
+	ProfileName   string
+
+	// statusOut, ilerleme, uyarı ve token kullanımı gibi bilgi satırlarının
+	// yazıldığı yerdir. Makine tarafından okunan çıktılarda (ör. audit -o json)
+	// stdout temiz kalsın diye stderr'e çevrilir.
+	statusOut io.Writer = os.Stdout
 
 	RootCmd = &cobra.Command{
 		Use:     "kube-ai",
 		Version: "0.1.0",
 		Short:   "AI-powered Kubernetes CLI assistant",
 		Long:    "Kube-AI provides AI-powered analysis, auditing, YAML generation, and troubleshooting for Kubernetes resources.",
+		// Hatalar main.go'da tek bir yerde yazdırılır ve çıkış koduna çevrilir
+		SilenceUsage:  true,
+		SilenceErrors: true,
 	}
 )
 
+// Execute, RootCmd'yi çalıştırır. Flag ve argüman doğrulama hataları
+// UsageError'a sarılır ki CI script'leri bunları çıkış kodu 2 ile ayırt edebilsin.
+func Execute(ctx context.Context) error {
+	wrapArgsValidators(RootCmd)
+	err := RootCmd.ExecuteContext(ctx)
+	if err != nil && strings.HasPrefix(err.Error(), "unknown command") {
+		return &UsageError{Err: err}
+	}
+	return err
+}
+
+func wrapArgsValidators(c *cobra.Command) {
+	if validate := c.Args; validate != nil {
+		c.Args = func(cmd *cobra.Command, args []string) error {
+			if err := validate(cmd, args); err != nil {
+				return &UsageError{Err: err}
+			}
+			return nil
+		}
+	}
+	for _, sub := range c.Commands() {
+		wrapArgsValidators(sub)
+	}
+}
+
 func init() {
+	// Kullanıcının vermediği flag'ler ortam değişkenlerinden ve config profilinden doldurulur
+	RootCmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		if cmd.Parent() == ConfigCmd {
+			// Bozuk bir config dosyası config komutlarıyla düzeltilebilsin
+			return nil
+		}
+		return applyConfig(cmd)
+	}
+
+	RootCmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		return &UsageError{Err: err}
+	})
+
 	// Global flags for all subcommands
 	RootCmd.PersistentFlags().StringVarP(&Model, "model", "m", "gpt-4o", "AI model to use (default: gpt-4o)")
 	RootCmd.PersistentFlags().IntVarP(&MaxTokens, "max-tokens", "t", 2048, "Maximum tokens for AI responses (default: 2048)")
-	RootCmd.PersistentFlags().BoolVarP(&CountTokens, "count-tokens", "c", false, "Print token usage after request")
+	RootCmd.PersistentFlags().BoolVarP(&CountTokens, "count-tokens", "c", false, "Print token usage and estimated cost after each AI request")
 	RootCmd.PersistentFlags().BoolVarP(&Verbose, "verbose", "v", false, "Enable verbose output")
-	RootCmd.PersistentFlags().IntVarP(&MaxIterations, "max-iterations", "x", 30, "Maximum iterations for multi-step analysis (reserved)")
+	RootCmd.PersistentFlags().IntVarP(&MaxIterations, "max-iterations", "x", 30, "Maximum tool-calling steps when analyze/diagnose investigate a live resource (1 disables)")
+	RootCmd.PersistentFlags().StringVar(&Kubeconfig, "kubeconfig", "", "Path to the kubeconfig file (default: $KUBECONFIG or ~/.kube/config)")
+	RootCmd.PersistentFlags().StringVar(&KubeContext, "context", "", "Kubeconfig context to use (default: current context)")
+	RootCmd.PersistentFlags().StringVarP(&KubeNamespace, "namespace", "n", "", "Default namespace (default: namespace of the kubeconfig context)")
+	RootCmd.PersistentFlags().StringVar(&ProviderName, "provider", "openai", "LLM provider: openai, azure, compatible, ollama, llamacpp")
+	RootCmd.PersistentFlags().StringVar(&BaseURL, "base-url", "", "Base URL of the LLM endpoint (Azure resource endpoint or any OpenAI-compatible server)")
+	RootCmd.PersistentFlags().BoolVar(&NoStream, "no-stream", false, "Wait for the full AI response instead of streaming it as it arrives")
+	RootCmd.PersistentFlags().DurationVar(&Timeout, "timeout", 5*time.Minute, "Maximum time for a single AI request including retries (0 disables)")
+	RootCmd.PersistentFlags().IntVar(&MaxRetries, "max-retries", 3, "Retries for rate-limited (429) or transient 5xx/network AI errors")
+	RootCmd.PersistentFlags().BoolVar(&ShowRedacted, "show-redacted", false, "List which sensitive values were masked before data was sent to the AI (values are never printed)")
+	RootCmd.PersistentFlags().BoolVar(&Raw, "raw", false, "Send manifests as-is instead of stripping managedFields, resourceVersion, uid, last-applied-configuration and shrinking large ConfigMaps")
+	RootCmd.PersistentFlags().IntVar(&ContextSize, "context-size", 0, "Context window of the model in tokens; larger inputs are analyzed in chunks (default: built-in table per model)")
+	RootCmd.PersistentFlags().StringVar(&ProfileName, "profile", "", "Config profile to use (default: $KUBE_AI_PROFILE or current-profile in ~/.config/kube-ai/config.yaml)")
+	RootCmd.PersistentFlags().StringVar(&APIVersion, "api-version", "", "API version for Azure OpenAI (default: provider default)")
 
 	// Register subcommands
 	RootCmd.AddCommand(
@@ -38,6 +116,8 @@ func init() {
 		VersionCmd,
 		ModifyCmd,
 		CompletionCmd,
+		PromptsCmd,
+		ConfigCmd,
 		HistoryCmd, // 🟡 Bu komutun history.go içinde tanımlı olduğundan emin olun
 	)
 }
\ No newline at end of file
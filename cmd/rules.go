@@ -0,0 +1,459 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	yaml "go.yaml.in/yaml/v3"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Bulgunun kaynağı: dahili kural mı, AI mı.
+const (
+	SourceRule = "rule"
+	SourceAI   = "ai"
+)
+
+// podTemplate, bir manifest'teki pod tanımıdır: Pod'un kendisi veya bir
+// workload'un pod şablonu.
+type podTemplate struct {
+	Resource string // kind/name, küçük harf
+	Kind     string
+	Path     string // pod spec'inin manifest içindeki yolu, örn. spec.template.spec
+	Spec     corev1.PodSpec
+	// Pod (şablonu) label ve annotation'ları ve metadata'nın yolu
+	Labels      map[string]string
+	Annotations map[string]string
+	MetaPath    string
+	Namespace   string // nesnenin namespace'i; verilmemişse boş
+}
+
+// podSpecPaths, pod şablonu içeren türlerde pod spec'inin yoludur.
+var podSpecPaths = map[string]string{
+	"Pod":         "spec",
+	"Deployment":  "spec.template.spec",
+	"StatefulSet": "spec.template.spec",
+	"DaemonSet":   "spec.template.spec",
+	"ReplicaSet":  "spec.template.spec",
+	"Job":         "spec.template.spec",
+	"CronJob":     "spec.jobTemplate.spec.template.spec",
+}
+
+// manifestObjects, metindeki YAML/JSON dokümanlarını nesnelere çevirir; List
+// ve items içeren dokümanlar elemanlarına açılır. Manifest olmayan metin
+// (örn. kubectl describe çıktısı) boş liste döndürür. Ayrıştırılamayan bir
+// Kubernetes nesnesi atlanır ve name ile birlikte statusOut'a uyarı yazılır.
+func manifestObjects(text, name string) []map[string]interface{} {
+	objects, skipped := decodeManifest(text)
+	for _, doc := range skipped {
+		fmt.Fprintf(statusOut, "⚠️ Skipped document %d (line %d) of %s, it could not be parsed and was not audited: %v\n",
+			doc.Index, doc.Line, name, doc.Err)
+	}
+	return objects
+}
+
+// decodeManifest, manifestObjects'in uyarı yazmayan halidir; ayrıştırılamayan
+// ve bir Kubernetes nesnesine benzeyen dokümanları da döndürür.
+func decodeManifest(text string) ([]map[string]interface{}, []manifestDocument) {
+	var objects []map[string]interface{}
+	var skipped []manifestDocument
+	for _, part := range splitManifest(text) {
+		dec := yaml.NewDecoder(strings.NewReader(part.Text))
+		for {
+			var doc map[string]interface{}
+			if err := dec.Decode(&doc); err == io.EOF {
+				break
+			} else if err != nil {
+				if objectPattern.MatchString(part.Text) {
+					part.Err = err
+					skipped = append(skipped, part)
+				}
+				break
+			}
+			if items, ok := doc["items"].([]interface{}); ok {
+				for _, item := range items {
+					if obj, ok := item.(map[string]interface{}); ok {
+						objects = append(objects, obj)
+					}
+				}
+				continue
+			}
+			if doc["kind"] != nil {
+				objects = append(objects, doc)
+			}
+		}
+	}
+	return objects, skipped
+}
+
+// objectPattern, bir dokümanın Kubernetes nesnesi olmaya çalıştığını gösterir.
+var objectPattern = regexp.MustCompile(`(?m)^kind:|"kind"\s*:`)
+
+// manifestDocument, "---" ile ayrılmış dokümanlardan biridir. Dokümanlar ayrı
+// ayrı okunur ki hatalı bir doküman sonrakilerin atlanmasına yol açmasın.
+type manifestDocument struct {
+	Text  string
+	Index int   // boş olmayan dokümanlar arasındaki sırası, 1'den başlar
+	Line  int   // metindeki ilk satırı, 1'den başlar
+	Err   error // ayrıştırma hatası; satırlar dokümana göredir
+}
+
+// splitManifest, metni doküman ayraçlarından böler; boş dokümanlar atlanır.
+func splitManifest(text string) []manifestDocument {
+	var docs []manifestDocument
+	start, line := 0, 1
+	bounds := append(yamlDocSeparator.FindAllStringIndex(text, -1), []int{len(text), len(text)})
+	for _, b := range bounds {
+		if part := text[start:b[0]]; strings.TrimSpace(part) != "" {
+			docs = append(docs, manifestDocument{Text: part, Index: len(docs) + 1, Line: line})
+		}
+		line += strings.Count(text[start:b[1]], "\n")
+		start = b[1]
+	}
+	return docs
+}
+
+// objectResource, nesneyi bulgularda kullanılan kind/name biçiminde adlandırır.
+func objectResource(obj map[string]interface{}) string {
+	kind, _ := obj["kind"].(string)
+	name := ""
+	if metadata, ok := obj["metadata"].(map[string]interface{}); ok {
+		name, _ = metadata["name"].(string)
+	}
+	return strings.ToLower(kind) + "/" + name
+}
+
+// podTemplates, nesnelerdeki pod tanımlarını çıkarır.
+func podTemplates(objects []map[string]interface{}) []podTemplate {
+	var templates []podTemplate
+	for _, obj := range objects {
+		kind, _ := obj["kind"].(string)
+		path, ok := podSpecPaths[kind]
+		if !ok {
+			continue
+		}
+		t := podTemplate{Resource: objectResource(obj), Kind: kind, Path: path, MetaPath: strings.TrimSuffix(path, "spec") + "metadata"}
+		node := lookupPath(obj, path)
+		if node == nil || decodeInto(node, &t.Spec) != nil {
+			continue
+		}
+		var meta struct {
+			Labels      map[string]string `json:"labels"`
+			Annotations map[string]string `json:"annotations"`
+		}
+		if node := lookupPath(obj, t.MetaPath); node != nil && decodeInto(node, &meta) == nil {
+			t.Labels, t.Annotations = meta.Labels, meta.Annotations
+		}
+		t.Namespace, _ = lookupPath(obj, "metadata.namespace").(string)
+		templates = append(templates, t)
+	}
+	return templates
+}
+
+// lookupPath, "spec.template.spec" gibi noktalı bir yoldaki değeri döndürür.
+func lookupPath(obj map[string]interface{}, path string) interface{} {
+	var node interface{} = obj
+	for _, key := range strings.Split(path, ".") {
+		m, _ := node.(map[string]interface{})
+		node = m[key]
+	}
+	return node
+}
+
+// decodeInto, ayrıştırılmış YAML değerini JSON üzerinden tipli bir yapıya çevirir.
+func decodeInto(node interface{}, out interface{}) error {
+	data, err := json.Marshal(node)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, out)
+}
+
+// containerRef, şablondaki bir container ve yoludur.
+type containerRef struct {
+	Container *corev1.Container
+	Path      string
+	Init      bool
+}
+
+// containers, init container'lar dahil tüm container'ları döndürür.
+func (t *podTemplate) containers() []containerRef {
+	var refs []containerRef
+	for i := range t.Spec.InitContainers {
+		refs = append(refs, containerRef{&t.Spec.InitContainers[i], fmt.Sprintf("%s.initContainers[%d]", t.Path, i), true})
+	}
+	for i := range t.Spec.Containers {
+		refs = append(refs, containerRef{&t.Spec.Containers[i], fmt.Sprintf("%s.containers[%d]", t.Path, i), false})
+	}
+	return refs
+}
+
+// securityRule, dahili bir denetim kuralıdır. check, eşleşen her alan için
+// bir bulgu döndürür; Severity boş bırakılırsa kuralınki kullanılır.
+type securityRule struct {
+	ID       string
+	Severity string
+	check    func(t *podTemplate) []Finding
+}
+
+// builtinRules, AI olmadan da çalışan deterministik kurallardır.
+var builtinRules = []securityRule{
+	{ID: "privileged-container", Severity: "critical", check: checkPrivileged},
+	{ID: "run-as-root", Severity: "high", check: checkRunAsRoot},
+	{ID: "host-network", Severity: "high", check: checkHostNetwork},
+	{ID: "host-path-volume", Severity: "high", check: checkHostPath},
+	{ID: "missing-resource-limits", Severity: "medium", check: checkLimits},
+	{ID: "image-latest-tag", Severity: "medium", check: checkLatestTag},
+	{ID: "missing-probes", Severity: "low", check: checkProbes},
+	{ID: "automount-service-account-token", Severity: "low", check: checkAutomountToken},
+}
+
+// isBuiltinRule, ruleId'nin dahili bir kurala ait olup olmadığını söyler.
+func isBuiltinRule(id string) bool {
+	for _, rule := range builtinRules {
+		if rule.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// runRules, dahili kuralları manifest'teki tüm pod tanımlarına uygular.
+func runRules(objects []map[string]interface{}) []Finding {
+	var findings []Finding
+	for _, t := range podTemplates(objects) {
+		for _, rule := range builtinRules {
+			for _, f := range rule.check(&t) {
+				f.RuleID = rule.ID
+				f.Resource = t.Resource
+				f.Namespace = t.Namespace
+				f.Source = SourceRule
+				if f.Severity == "" {
+					f.Severity = rule.Severity
+				}
+				findings = append(findings, f)
+			}
+		}
+	}
+	sortFindings(findings)
+	return findings
+}
+
+func checkPrivileged(t *podTemplate) []Finding {
+	var findings []Finding
+	for _, c := range t.containers() {
+		if sc := c.Container.SecurityContext; sc != nil && sc.Privileged != nil && *sc.Privileged {
+			findings = append(findings, Finding{
+				FieldPath:   c.Path + ".securityContext.privileged",
+				Description: fmt.Sprintf("Container %q runs privileged and has full access to the host.", c.Container.Name),
+				Remediation: "Remove securityContext.privileged or set it to false; grant only the capabilities the container needs.",
+			})
+		}
+	}
+	return findings
+}
+
+func checkRunAsRoot(t *podTemplate) []Finding {
+	var findings []Finding
+	pod := t.Spec.SecurityContext
+	for _, c := range t.containers() {
+		sc := c.Container.SecurityContext
+		// Container ayarı pod ayarını ezer
+		var runAsUser *int64
+		var runAsNonRoot *bool
+		if pod != nil {
+			runAsUser, runAsNonRoot = pod.RunAsUser, pod.RunAsNonRoot
+		}
+		userPath, nonRootPath := t.Path+".securityContext.runAsUser", c.Path+".securityContext.runAsNonRoot"
+		if sc != nil && sc.RunAsUser != nil {
+			runAsUser, userPath = sc.RunAsUser, c.Path+".securityContext.runAsUser"
+		}
+		if sc != nil && sc.RunAsNonRoot != nil {
+			runAsNonRoot = sc.RunAsNonRoot
+		}
+
+		switch {
+		case runAsUser != nil && *runAsUser == 0:
+			findings = append(findings, Finding{
+				FieldPath:   userPath,
+				Description: fmt.Sprintf("Container %q explicitly runs as root (UID 0).", c.Container.Name),
+				Remediation: "Set runAsUser to a non-zero UID and runAsNonRoot: true.",
+			})
+		case runAsUser == nil && (runAsNonRoot == nil || !*runAsNonRoot):
+			findings = append(findings, Finding{
+				Severity:    "medium",
+				FieldPath:   nonRootPath,
+				Description: fmt.Sprintf("Container %q may run as root: runAsNonRoot is not set and no non-root runAsUser is given.", c.Container.Name),
+				Remediation: "Set securityContext.runAsNonRoot: true (and a non-zero runAsUser if the image defaults to root).",
+			})
+		}
+	}
+	return findings
+}
+
+func checkHostNetwork(t *podTemplate) []Finding {
+	if !t.Spec.HostNetwork {
+		return nil
+	}
+	return []Finding{{
+		FieldPath:   t.Path + ".hostNetwork",
+		Description: "Pod uses the host network namespace and can see and bind to all host interfaces.",
+		Remediation: "Remove hostNetwork: true and expose the pod through a Service instead.",
+	}}
+}
+
+func checkHostPath(t *podTemplate) []Finding {
+	var findings []Finding
+	for i, v := range t.Spec.Volumes {
+		if v.HostPath != nil {
+			findings = append(findings, Finding{
+				FieldPath:   fmt.Sprintf("%s.volumes[%d].hostPath", t.Path, i),
+				Description: fmt.Sprintf("Volume %q mounts host path %s, which exposes the node's filesystem.", v.Name, v.HostPath.Path),
+				Remediation: "Replace the hostPath volume with a PersistentVolumeClaim, configMap, secret or emptyDir.",
+			})
+		}
+	}
+	return findings
+}
+
+func checkLimits(t *podTemplate) []Finding {
+	var findings []Finding
+	for _, c := range t.containers() {
+		var missing []string
+		for _, name := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory} {
+			if _, ok := c.Container.Resources.Limits[name]; !ok {
+				missing = append(missing, string(name))
+			}
+		}
+		if len(missing) > 0 {
+			findings = append(findings, Finding{
+				FieldPath:   c.Path + ".resources.limits",
+				Description: fmt.Sprintf("Container %q has no %s limit and can exhaust node resources.", c.Container.Name, strings.Join(missing, "/")),
+				Remediation: "Set resources.limits (and requests) for cpu and memory.",
+			})
+		}
+	}
+	return findings
+}
+
+func checkLatestTag(t *podTemplate) []Finding {
+	var findings []Finding
+	for _, c := range t.containers() {
+		if c.Container.Image != "" && imageUsesLatest(c.Container.Image) {
+			findings = append(findings, Finding{
+				FieldPath:   c.Path + ".image",
+				Description: fmt.Sprintf("Container %q uses image %s without a fixed tag, so deployments are not reproducible.", c.Container.Name, c.Container.Image),
+				Remediation: "Pin the image to a specific version tag or, better, a digest (image@sha256:...).",
+			})
+		}
+	}
+	return findings
+}
+
+// imageUsesLatest, imaj etiketsizse veya :latest ise ve digest içermiyorsa true döner.
+func imageUsesLatest(image string) bool {
+	if strings.Contains(image, "@") {
+		return false
+	}
+	name := image[strings.LastIndex(image, "/")+1:]
+	tag, ok := "", false
+	if i := strings.LastIndex(name, ":"); i >= 0 {
+		tag, ok = name[i+1:], true
+	}
+	return !ok || tag == "latest"
+}
+
+func checkProbes(t *podTemplate) []Finding {
+	// Job'lar tamamlanıp çıkar; probe beklenmez
+	if t.Kind == "Job" || t.Kind == "CronJob" {
+		return nil
+	}
+	var findings []Finding
+	for _, c := range t.containers() {
+		if c.Init {
+			continue
+		}
+		var missing []string
+		if c.Container.LivenessProbe == nil {
+			missing = append(missing, "livenessProbe")
+		}
+		if c.Container.ReadinessProbe == nil {
+			missing = append(missing, "readinessProbe")
+		}
+		if len(missing) > 0 {
+			findings = append(findings, Finding{
+				FieldPath:   c.Path + "." + missing[0],
+				Description: fmt.Sprintf("Container %q has no %s; failures are not detected and traffic may reach unready pods.", c.Container.Name, strings.Join(missing, " or ")),
+				Remediation: "Add " + strings.Join(missing, " and ") + " checking the application's health endpoint or port.",
+			})
+		}
+	}
+	return findings
+}
+
+func checkAutomountToken(t *podTemplate) []Finding {
+	if token := t.Spec.AutomountServiceAccountToken; token != nil && !*token {
+		return nil
+	}
+	return []Finding{{
+		FieldPath:   t.Path + ".automountServiceAccountToken",
+		Description: "The service account token is mounted into the pod; a compromised container can use it against the API server.",
+		Remediation: "Set automountServiceAccountToken: false unless the pod talks to the Kubernetes API.",
+	}}
+}
+
+// mergeFindings, dahili kural bulgularını AI bulgularıyla birleştirir. Kural
+// bulguları olduğu gibi kalır; AI'ın aynı bulgu için yazdığı açıklama
+// Explanation'a eklenir. AI'ın dahili kural adıyla bildirdiği diğer bulgular
+// atılır, yalnızca kuralların kapsamadığı bulgular eklenir.
+func mergeFindings(rules, ai []Finding) []Finding {
+	key := func(f Finding) string { return f.RuleID + "|" + f.Resource + "|" + f.FieldPath }
+	explanations := make(map[string]string)
+	merged := append([]Finding(nil), rules...)
+	for _, f := range ai {
+		if isBuiltinRule(f.RuleID) || isPSSControl(f.RuleID) || isRBACCheck(f.RuleID) || isNetpolCheck(f.RuleID) || isOrgPolicy(f.RuleID) {
+			explanations[key(f)] = f.Description
+			continue
+		}
+		f.Source = SourceAI
+		merged = append(merged, f)
+	}
+	for i := range merged {
+		if merged[i].Source != SourceAI {
+			merged[i].Explanation = explanations[key(merged[i])]
+		}
+	}
+	sortFindings(merged)
+	return merged
+}
+
+// fillNamespaces, namespace'i boş olan (AI) bulgulara manifest'teki
+// nesnenin namespace'ini yazar.
+func fillNamespaces(findings []Finding, objects []map[string]interface{}) {
+	namespaces := make(map[string]string)
+	for _, obj := range objects {
+		namespaces[objectResource(obj)], _ = lookupPath(obj, "metadata.namespace").(string)
+	}
+	for i := range findings {
+		if findings[i].Namespace == "" {
+			findings[i].Namespace = namespaces[strings.ToLower(findings[i].Resource)]
+		}
+	}
+}
+
+// ruleFindingsPrompt, kural bulgularını AI'ın açıklaması için kullanıcı mesajına ekler.
+func ruleFindingsPrompt(findings []Finding) string {
+	if len(findings) == 0 {
+		return "\n\nThe built-in checks found no problems. Report only problems they do not cover."
+	}
+	data, _ := marshalJSON(AuditReport{Findings: findings}, "  ")
+	return fmt.Sprintf(`
+
+The built-in checks and organization policies already found the findings below. They are authoritative; do not change their ruleId, severity, resource or fieldPath.
+For each one, report an entry with the same ruleId, resource and fieldPath whose description explains the concrete risk in the context of this manifest.
+Then add findings only for problems the built-in checks do not cover, with a different ruleId.
+%s`, data)
+}
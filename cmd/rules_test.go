@@ -0,0 +1,312 @@
+package cmd
+
+import (
+	"bytes"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// findingKeys, bulguları karşılaştırma için "ruleId severity resource fieldPath" biçiminde sıralı döndürür.
+func findingKeys(findings []Finding) []string {
+	keys := make([]string, len(findings))
+	for i, f := range findings {
+		keys[i] = strings.Join([]string{f.RuleID, f.Severity, f.Resource, f.FieldPath}, " ")
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// assertFindings, bulguların beklenen anahtarlarla birebir eşleştiğini doğrular.
+func assertFindings(t *testing.T, findings []Finding, want []string) {
+	t.Helper()
+	slices.Sort(want)
+	if got := findingKeys(findings); !slices.Equal(got, want) {
+		t.Errorf("findings:\n  got  %q\n  want %q", got, want)
+	}
+}
+
+// captureStatus, testin statusOut'a yazdıklarını toplar.
+func captureStatus(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	previous := statusOut
+	statusOut = &buf
+	t.Cleanup(func() { statusOut = previous })
+	return &buf
+}
+
+// quietStatus, statusOut'u test boyunca susturur.
+func quietStatus(t *testing.T) {
+	t.Helper()
+	previous := statusOut
+	statusOut = io.Discard
+	t.Cleanup(func() { statusOut = previous })
+}
+
+func TestRunRules(t *testing.T) {
+	tests := []struct {
+		name     string
+		manifest string
+		want     []string
+	}{
+		{
+			name: "hardened deployment",
+			manifest: `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  template:
+    spec:
+      automountServiceAccountToken: false
+      securityContext:
+        runAsNonRoot: true
+      containers:
+      - name: web
+        image: nginx:1.27
+        resources:
+          limits: {cpu: 500m, memory: 256Mi}
+        livenessProbe: {httpGet: {path: /, port: 80}}
+        readinessProbe: {httpGet: {path: /, port: 80}}
+`,
+			want: []string{},
+		},
+		{
+			name: "privileged root container",
+			manifest: `apiVersion: v1
+kind: Pod
+metadata:
+  name: debug
+spec:
+  automountServiceAccountToken: false
+  containers:
+  - name: shell
+    image: busybox:1.36
+    securityContext:
+      privileged: true
+      runAsUser: 0
+    resources:
+      limits: {cpu: 100m, memory: 64Mi}
+    livenessProbe: {exec: {command: ["true"]}}
+    readinessProbe: {exec: {command: ["true"]}}
+`,
+			want: []string{
+				"privileged-container critical pod/debug spec.containers[0].securityContext.privileged",
+				"run-as-root high pod/debug spec.containers[0].securityContext.runAsUser",
+			},
+		},
+		{
+			name: "host access and defaults",
+			manifest: `apiVersion: v1
+kind: Pod
+metadata:
+  name: agent
+spec:
+  hostNetwork: true
+  securityContext:
+    runAsUser: 1000
+  volumes:
+  - name: logs
+    hostPath: {path: /var/log}
+  containers:
+  - name: agent
+    image: registry.local:5000/agent
+`,
+			want: []string{
+				"host-network high pod/agent spec.hostNetwork",
+				"host-path-volume high pod/agent spec.volumes[0].hostPath",
+				"image-latest-tag medium pod/agent spec.containers[0].image",
+				"missing-resource-limits medium pod/agent spec.containers[0].resources.limits",
+				"missing-probes low pod/agent spec.containers[0].livenessProbe",
+				"automount-service-account-token low pod/agent spec.automountServiceAccountToken",
+			},
+		},
+		{
+			name: "cronjob without probes or runAsNonRoot",
+			manifest: `apiVersion: batch/v1
+kind: CronJob
+metadata:
+  name: backup
+spec:
+  jobTemplate:
+    spec:
+      template:
+        spec:
+          automountServiceAccountToken: false
+          containers:
+          - name: backup
+            image: backup:2.1@sha256:abc
+            resources:
+              limits: {cpu: "1", memory: 1Gi}
+`,
+			want: []string{
+				"run-as-root medium cronjob/backup spec.jobTemplate.spec.template.spec.containers[0].securityContext.runAsNonRoot",
+			},
+		},
+		{
+			name: "container overrides pod user",
+			manifest: `apiVersion: v1
+kind: Pod
+metadata:
+  name: mixed
+spec:
+  automountServiceAccountToken: false
+  securityContext:
+    runAsUser: 1000
+  initContainers:
+  - name: setup
+    image: setup:1
+    securityContext:
+      runAsUser: 0
+    resources:
+      limits: {cpu: 100m, memory: 64Mi}
+  containers:
+  - name: app
+    image: app:1
+    resources:
+      limits: {cpu: 100m, memory: 64Mi}
+    livenessProbe: {tcpSocket: {port: 80}}
+    readinessProbe: {tcpSocket: {port: 80}}
+`,
+			want: []string{
+				"run-as-root high pod/mixed spec.initContainers[0].securityContext.runAsUser",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			quietStatus(t)
+			assertFindings(t, runRules(manifestObjects(tt.manifest, "test.yaml")), tt.want)
+		})
+	}
+}
+
+func TestManifestObjects(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		want    []string // objectResource
+		warning string   // statusOut'ta beklenen metin; boşsa uyarı yazılmamalı
+	}{
+		{
+			name: "bad document in the middle",
+			text: `kind: Pod
+metadata: {name: first}
+---
+kind: Pod
+metadata:
+  name: broken
+  labels: [unclosed
+---
+kind: Pod
+metadata: {name: third}
+`,
+			want:    []string{"pod/first", "pod/third"},
+			warning: "Skipped document 2 (line 4) of test.yaml",
+		},
+		{
+			name: "list is expanded",
+			text: `{"kind": "List", "items": [{"kind": "Pod", "metadata": {"name": "a"}}, {"kind": "Service", "metadata": {"name": "b"}}]}`,
+			want: []string{"pod/a", "service/b"},
+		},
+		{
+			name: "describe output is not a manifest",
+			text: "Name:         web\nNamespace:    default\nEvents:\n  Warning  BackOff  [x\n",
+			want: []string{},
+		},
+		{
+			name: "leading separator and empty documents",
+			text: "---\nkind: Pod\nmetadata: {name: a}\n---\n---\nkind: Pod\nmetadata: {name: b}\n",
+			want: []string{"pod/a", "pod/b"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status := captureStatus(t)
+			got := []string{}
+			for _, obj := range manifestObjects(tt.text, "test.yaml") {
+				got = append(got, objectResource(obj))
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("objects = %q, want %q", got, tt.want)
+			}
+			if tt.warning == "" && status.Len() > 0 {
+				t.Errorf("unexpected warning: %s", status)
+			}
+			if tt.warning != "" && !strings.Contains(status.String(), tt.warning) {
+				t.Errorf("warning %q not in %q", tt.warning, status)
+			}
+		})
+	}
+}
+
+func TestYAMLDocumentsKeepsLines(t *testing.T) {
+	source := `kind: Pod
+metadata: {name: a}
+---
+kind: Pod
+metadata: [broken
+---
+kind: Pod
+metadata:
+  name: c
+spec:
+  hostNetwork: true
+`
+	docs := yamlDocuments(source)
+	doc := findDocument(docs, "pod/c")
+	if doc == nil {
+		t.Fatalf("pod/c not found after a broken document")
+	}
+	if line := fieldLine(doc, "spec.hostNetwork"); line != 11 {
+		t.Errorf("spec.hostNetwork is on line %d, want 11", line)
+	}
+}
+
+func TestImageUsesLatest(t *testing.T) {
+	tests := map[string]bool{
+		"nginx":                          true,
+		"nginx:latest":                   true,
+		"nginx:1.27":                     false,
+		"registry.local:5000/team/app":   true,
+		"registry.local:5000/team/app:2": false,
+		"nginx@sha256:0123":              false,
+		"nginx:latest@sha256:0123":       false,
+	}
+	for image, want := range tests {
+		if got := imageUsesLatest(image); got != want {
+			t.Errorf("imageUsesLatest(%q) = %v, want %v", image, got, want)
+		}
+	}
+}
+
+func TestMergeFindings(t *testing.T) {
+	rules := []Finding{{RuleID: "host-network", Severity: "high", Resource: "pod/a", FieldPath: "spec.hostNetwork", Source: SourceRule}}
+	ai := []Finding{
+		// Kural bulgusunun açıklaması
+		{RuleID: "host-network", Severity: "low", Resource: "pod/a", FieldPath: "spec.hostNetwork", Description: "The pod can sniff node traffic."},
+		// Kuralın adıyla yeni bulgu uyduramaz
+		{RuleID: "privileged-container", Severity: "critical", Resource: "pod/a", FieldPath: "spec.containers[0]"},
+		// Kuralların kapsamadığı bulgu eklenir
+		{RuleID: "exposed-dashboard", Severity: "medium", Resource: "pod/a", FieldPath: "spec.containers[0].ports[0]"},
+	}
+	merged := mergeFindings(rules, ai)
+	assertFindings(t, merged, []string{
+		"host-network high pod/a spec.hostNetwork",
+		"exposed-dashboard medium pod/a spec.containers[0].ports[0]",
+	})
+	for _, f := range merged {
+		switch f.RuleID {
+		case "host-network":
+			if f.Source != SourceRule || f.Explanation != "The pod can sniff node traffic." {
+				t.Errorf("rule finding = %+v, want source rule with the AI explanation", f)
+			}
+		case "exposed-dashboard":
+			if f.Source != SourceAI {
+				t.Errorf("AI finding has source %q", f.Source)
+			}
+		}
+	}
+}
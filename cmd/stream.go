@@ -0,0 +1,170 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	openai "github.com/sashabaranov/go-openai"
+)
+
+// complete, isteği gönderir ve cevap metnini out'a yazar. Streaming açıksa
+// (--no-stream verilmediyse) metin geldikçe yazılır; aksi halde tam yanıt
+// beklenir. Her iki durumda da birleştirilmiş yanıt (araç çağrıları ve token
+// kullanımı dahil) döndürülür. Hatalar AIError olarak sınıflandırılır.
+func complete(ctx context.Context, client Provider, req openai.ChatCompletionRequest, out io.Writer) (openai.ChatCompletionResponse, error) {
+	if Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, Timeout)
+		defer cancel()
+	}
+
+	resp, err := completeRaw(ctx, client, req, out)
+	return resp, classifyAIError(err)
+}
+
+func completeRaw(ctx context.Context, client Provider, req openai.ChatCompletionRequest, out io.Writer) (openai.ChatCompletionResponse, error) {
+	if NoStream {
+		resp, err := client.CreateChatCompletion(ctx, req)
+		if err != nil {
+			return resp, err
+		}
+		if len(resp.Choices) == 0 {
+			return resp, fmt.Errorf("empty response from model")
+		}
+		if content := strings.TrimSpace(resp.Choices[0].Message.Content); content != "" {
+			fmt.Fprintln(out, content)
+		}
+		return resp, nil
+	}
+
+	req.Stream = true
+	req.StreamOptions = &openai.StreamOptions{IncludeUsage: true}
+	stream, err := client.CreateChatCompletionStream(ctx, req)
+	if err != nil {
+		return openai.ChatCompletionResponse{}, err
+	}
+	defer stream.Close()
+
+	resp := openai.ChatCompletionResponse{Model: req.Model}
+	var content strings.Builder
+	var toolCalls []openai.ToolCall
+	var finish openai.FinishReason
+	for {
+		chunk, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return resp, err
+		}
+
+		resp.ID = chunk.ID
+		if chunk.Model != "" {
+			resp.Model = chunk.Model
+		}
+		if chunk.Usage != nil {
+			resp.Usage = *chunk.Usage
+		}
+		if len(chunk.Choices) == 0 {
+			continue
+		}
+
+		choice := chunk.Choices[0]
+		if choice.FinishReason != "" {
+			finish = choice.FinishReason
+		}
+		if delta := choice.Delta.Content; delta != "" {
+			// Baştaki boşlukları atla, non-stream çıktıyla aynı görünsün
+			if content.Len() == 0 {
+				delta = strings.TrimLeft(delta, " \n")
+			}
+			content.WriteString(delta)
+			fmt.Fprint(out, delta)
+		}
+		toolCalls = mergeToolCallDeltas(toolCalls, choice.Delta.ToolCalls)
+	}
+
+	text := content.String()
+	if text != "" && !strings.HasSuffix(text, "\n") {
+		fmt.Fprintln(out)
+	}
+	if f, ok := out.(interface{ Flush() }); ok {
+		f.Flush()
+	}
+
+	resp.Choices = []openai.ChatCompletionChoice{{
+		Message: openai.ChatCompletionMessage{
+			Role:      openai.ChatMessageRoleAssistant,
+			Content:   text,
+			ToolCalls: toolCalls,
+		},
+		FinishReason: finish,
+	}}
+	return resp, nil
+}
+
+// mergeToolCallDeltas, stream'de parça parça gelen araç çağrılarını index'e göre birleştirir.
+func mergeToolCallDeltas(calls []openai.ToolCall, deltas []openai.ToolCall) []openai.ToolCall {
+	for _, delta := range deltas {
+		index := len(calls)
+		if delta.Index != nil {
+			index = *delta.Index
+		}
+		for len(calls) <= index {
+			calls = append(calls, openai.ToolCall{Type: openai.ToolTypeFunction})
+		}
+
+		call := &calls[index]
+		if delta.ID != "" {
+			call.ID = delta.ID
+		}
+		if delta.Function.Name != "" {
+			call.Function.Name = delta.Function.Name
+		}
+		call.Function.Arguments += delta.Function.Arguments
+	}
+	return calls
+}
+
+var fenceRe = regexp.MustCompile("(?i)^```(?:yaml)?$")
+
+// fenceFilter, satır satır yazar ve ``` veya ```yaml fence satırlarını atar.
+// generate komutunun stream çıktısı için kullanılır.
+type fenceFilter struct {
+	out io.Writer
+	buf bytes.Buffer
+}
+
+func (f *fenceFilter) Write(p []byte) (int, error) {
+	f.buf.Write(p)
+	for {
+		line, err := f.buf.ReadString('\n')
+		if err != nil {
+			// Tamamlanmamış satırı bir sonraki yazmaya sakla
+			f.buf.Reset()
+			f.buf.WriteString(line)
+			return len(p), nil
+		}
+		f.writeLine(line)
+	}
+}
+
+// Flush, son (yeni satırla bitmeyen) satırı yazar.
+func (f *fenceFilter) Flush() {
+	if f.buf.Len() > 0 {
+		f.writeLine(f.buf.String())
+		f.buf.Reset()
+	}
+}
+
+func (f *fenceFilter) writeLine(line string) {
+	if fenceRe.MatchString(strings.TrimSpace(line)) {
+		return
+	}
+	fmt.Fprint(f.out, line)
+}
@@ -0,0 +1,278 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	yaml "go.yaml.in/yaml/v3"
+)
+
+// defaultIgnoreFile, --baseline verilmediğinde çalışma dizininde aranan
+// bastırma dosyasıdır.
+const defaultIgnoreFile = ".kube-ai-ignore.yaml"
+
+// expiryLayout, bastırmaların expires alanının biçimidir.
+const expiryLayout = "2006-01-02"
+
+// Suppression, kabul edilmiş bir riski bastırır. Boş bırakılan alanlar her
+// değerle eşleşir; en az biri verilmelidir. fieldPath, alt alanları da kapsar.
+type Suppression struct {
+	RuleID        string `yaml:"ruleId,omitempty"`
+	Resource      string `yaml:"resource,omitempty"`
+	Namespace     string `yaml:"namespace,omitempty"`
+	FieldPath     string `yaml:"fieldPath,omitempty"`
+	Justification string `yaml:"justification"`
+	// Expires verilmişse bastırma o günün sonuna kadar geçerlidir (YYYY-MM-DD).
+	Expires string `yaml:"expires,omitempty"`
+}
+
+// SuppressionFile, .kube-ai-ignore.yaml dosyasının içeriğidir.
+type SuppressionFile struct {
+	Suppressions []Suppression `yaml:"suppressions"`
+}
+
+// matches, bastırmanın bulguyu kapsayıp kapsamadığını söyler.
+func (s Suppression) matches(f Finding) bool {
+	if s.RuleID != "" && s.RuleID != f.RuleID {
+		return false
+	}
+	if s.Resource != "" && !strings.EqualFold(s.Resource, f.Resource) {
+		return false
+	}
+	if s.Namespace != "" && s.Namespace != f.Namespace {
+		return false
+	}
+	if s.FieldPath != "" && s.FieldPath != f.FieldPath {
+		rest, ok := strings.CutPrefix(f.FieldPath, s.FieldPath)
+		if !ok || !strings.HasPrefix(rest, ".") && !strings.HasPrefix(rest, "[") {
+			return false
+		}
+	}
+	return true
+}
+
+// expired, bastırmanın süresinin dolup dolmadığını söyler.
+func (s Suppression) expired(now time.Time) bool {
+	if s.Expires == "" {
+		return false
+	}
+	day, err := time.ParseInLocation(expiryLayout, s.Expires, now.Location())
+	return err == nil && !now.Before(day.AddDate(0, 0, 1))
+}
+
+// String, bastırmayı uyarılarda kullanılacak biçimde özetler.
+func (s Suppression) String() string {
+	var parts []string
+	for _, field := range []struct{ name, value string }{
+		{"ruleId", s.RuleID}, {"resource", s.Resource}, {"namespace", s.Namespace}, {"fieldPath", s.FieldPath},
+	} {
+		if field.value != "" {
+			parts = append(parts, field.name+"="+field.value)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// suppressions, validateFindingsFlags'ın yüklediği bastırma dosyasıdır; hatalı
+// dosya AI çağrısından önce bildirilir.
+var suppressions *SuppressionFile
+
+// suppressionPath, --baseline veya varsayılan bastırma dosyasının yoludur.
+func suppressionPath() string {
+	if auditBaseline != "" {
+		return auditBaseline
+	}
+	return defaultIgnoreFile
+}
+
+// loadSuppressions, bastırma dosyasını okur ve doğrular. Varsayılan dosya
+// yoksa (veya --write-baseline ile oluşturulacaksa) boş döner.
+func loadSuppressions(path string) (*SuppressionFile, error) {
+	file := &SuppressionFile{}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) && (auditBaseline == "" || auditWriteBaseline) {
+		return file, nil
+	}
+	if err != nil {
+		return nil, usageErrorf("failed to read suppression file %s: %w", path, err)
+	}
+	if err := yaml.Unmarshal(data, file); err != nil {
+		return nil, usageErrorf("failed to parse suppression file %s: %w", path, err)
+	}
+	for i, s := range file.Suppressions {
+		if s.String() == "" {
+			return nil, usageErrorf("suppression %d in %s matches every finding; set ruleId, resource, namespace or fieldPath", i+1, path)
+		}
+		if strings.TrimSpace(s.Justification) == "" {
+			return nil, usageErrorf("suppression %d in %s (%s) has no justification", i+1, path, s)
+		}
+		if s.Expires != "" {
+			if _, err := time.Parse(expiryLayout, s.Expires); err != nil {
+				return nil, usageErrorf("suppression %d in %s has invalid expires %q (use YYYY-MM-DD)", i+1, path, s.Expires)
+			}
+		}
+	}
+	return file, nil
+}
+
+// suppressionHeader, --write-baseline'ın oluşturduğu dosyanın başlığıdır.
+const suppressionHeader = "# kube-ai suppressions: every entry needs a justification; expires (YYYY-MM-DD) is optional.\n"
+
+// writeSuppressions, eklenen bastırmaları dosyanın sonuna yazar. Dosya yoksa
+// başlıkla oluşturulur; varsa yaml.Node üzerinden okunur ki kullanıcının
+// yorumları, bilinmeyen alanlar ve biçim korunsun.
+func writeSuppressions(path string, added []Suppression) error {
+	source, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read suppression file %s: %w", path, err)
+	}
+	if len(source) > 0 && len(added) == 0 {
+		return nil
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(source, &doc); err != nil {
+		return usageErrorf("failed to parse suppression file %s: %w", path, err)
+	}
+	if len(doc.Content) == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}}
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return usageErrorf("suppression file %s must be a mapping with a suppressions list", path)
+	}
+	list := mappingValue(root, "suppressions")
+	if list == nil {
+		list = &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		key := scalarNode("suppressions")
+		if len(source) == 0 {
+			// Belge başı yorumu ayrı paragraf olarak yazılır; başlık anahtara eklenir
+			key.HeadComment = strings.TrimSpace(suppressionHeader)
+		}
+		root.Content = append(root.Content, key, list)
+	}
+	if list.Kind == yaml.ScalarNode && list.Tag == "!!null" {
+		list.Kind, list.Tag, list.Value = yaml.SequenceNode, "!!seq", ""
+	}
+	if list.Kind != yaml.SequenceNode {
+		return usageErrorf("suppressions in %s must be a list", path)
+	}
+	if len(list.Content) == 0 {
+		list.Style = 0 // "suppressions: []" blok listeye dönüşür
+	}
+	for _, s := range added {
+		var entry yaml.Node
+		if err := entry.Encode(s); err != nil {
+			return err
+		}
+		list.Content = append(list.Content, &entry)
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if len(source) == 0 || compactSequences(string(source)) {
+		enc.CompactSeqIndent()
+	}
+	if err := enc.Encode(&doc); err != nil {
+		return err
+	}
+	if err := enc.Close(); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write suppression file %s: %w", path, err)
+	}
+	return nil
+}
+
+// suppressionResult, bastırmaların uygulanmasının özetidir.
+type suppressionResult struct {
+	Path       string
+	Suppressed int
+	Baselined  int           // --write-baseline ile eklenen bastırmalar
+	Expired    []Suppression // süresi dolduğu için bulguları yeniden bildirilenler
+}
+
+// applySuppressions, bastırma dosyasındaki geçerli kayıtların kapsadığı
+// bulguları çıkarır. --write-baseline ile bastırılmamış bulgular önce dosyaya
+// eklenir; böylece sonraki çalıştırmalarda yalnızca yeni bulgular bildirilir.
+func applySuppressions(findings []Finding) ([]Finding, suppressionResult, error) {
+	result := suppressionResult{Path: suppressionPath()}
+	file := suppressions
+	if file == nil {
+		file = &SuppressionFile{}
+	}
+
+	now := time.Now()
+	active := func(f Finding) (bool, []Suppression) {
+		var expired []Suppression
+		for _, s := range file.Suppressions {
+			if !s.matches(f) {
+				continue
+			}
+			if !s.expired(now) {
+				return true, nil
+			}
+			expired = append(expired, s)
+		}
+		return false, expired
+	}
+
+	if auditWriteBaseline {
+		var added []Suppression
+		justification := "Accepted in baseline on " + now.Format(expiryLayout)
+		for _, f := range findings {
+			if ok, _ := active(f); ok {
+				continue
+			}
+			s := Suppression{
+				RuleID:        f.RuleID,
+				Resource:      f.Resource,
+				Namespace:     f.Namespace,
+				FieldPath:     f.FieldPath,
+				Justification: justification,
+			}
+			file.Suppressions = append(file.Suppressions, s)
+			added = append(added, s)
+		}
+		result.Baselined = len(added)
+		if err := writeSuppressions(result.Path, added); err != nil {
+			return nil, result, err
+		}
+	}
+
+	var kept []Finding
+	seen := make(map[string]bool)
+	for _, f := range findings {
+		ok, expired := active(f)
+		if ok {
+			result.Suppressed++
+			continue
+		}
+		for _, s := range expired {
+			if key := s.String() + "|" + s.Expires; !seen[key] {
+				seen[key] = true
+				result.Expired = append(result.Expired, s)
+			}
+		}
+		kept = append(kept, f)
+	}
+	return kept, result, nil
+}
+
+// writeSuppressionSummary, bastırılan bulguları ve süresi dolan bastırmaları yazar.
+func writeSuppressionSummary(result suppressionResult) {
+	if result.Baselined > 0 {
+		fmt.Fprintf(statusOut, "📝 Added %d finding(s) to baseline %s\n", result.Baselined, result.Path)
+	}
+	if result.Suppressed > 0 {
+		fmt.Fprintf(statusOut, "🙈 %d finding(s) suppressed by %s\n", result.Suppressed, result.Path)
+	}
+	for _, s := range result.Expired {
+		fmt.Fprintf(statusOut, "⚠️ Suppression %s in %s expired on %s; its findings are reported again\n", s, result.Path, s.Expires)
+	}
+}
@@ -0,0 +1,258 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSuppressionMatches(t *testing.T) {
+	finding := Finding{
+		RuleID:    "image-latest-tag",
+		Resource:  "deployment/web",
+		Namespace: "shop",
+		FieldPath: "spec.template.spec.containers[0].image",
+	}
+	tests := []struct {
+		name string
+		s    Suppression
+		want bool
+	}{
+		{"rule only", Suppression{RuleID: "image-latest-tag"}, true},
+		{"other rule", Suppression{RuleID: "host-network"}, false},
+		{"resource is case insensitive", Suppression{Resource: "Deployment/web"}, true},
+		{"other namespace", Suppression{Resource: "deployment/web", Namespace: "prod"}, false},
+		{"exact field path", Suppression{FieldPath: "spec.template.spec.containers[0].image"}, true},
+		{"parent field", Suppression{FieldPath: "spec.template.spec"}, true},
+		{"parent list", Suppression{FieldPath: "spec.template.spec.containers"}, true},
+		{"field name prefix is not a parent", Suppression{FieldPath: "spec.template.spec.container"}, false},
+		{"sibling field", Suppression{FieldPath: "spec.template.spec.containers[0].imagePullPolicy"}, false},
+		{"other list item", Suppression{FieldPath: "spec.template.spec.containers[1]"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.s.matches(finding); got != tt.want {
+				t.Errorf("matches = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// imagePullPolicy bulgusu image bastırmasıyla bastırılmamalı
+	pull := finding
+	pull.FieldPath = "spec.template.spec.containers[0].imagePullPolicy"
+	if (Suppression{FieldPath: "spec.template.spec.containers[0].image"}).matches(pull) {
+		t.Error("suppression of image also matches imagePullPolicy")
+	}
+}
+
+func TestSuppressionExpired(t *testing.T) {
+	zone := time.FixedZone("UTC+3", 3*60*60)
+	s := Suppression{RuleID: "host-network", Expires: "2026-03-31"}
+	tests := []struct {
+		name string
+		now  time.Time
+		want bool
+	}{
+		{"day before", time.Date(2026, 3, 30, 12, 0, 0, 0, zone), false},
+		{"start of the expiry day", time.Date(2026, 3, 31, 0, 0, 0, 0, zone), false},
+		{"end of the expiry day", time.Date(2026, 3, 31, 23, 59, 59, 0, zone), false},
+		{"midnight after", time.Date(2026, 4, 1, 0, 0, 0, 0, zone), true},
+		{"later", time.Date(2026, 6, 1, 9, 0, 0, 0, zone), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := s.expired(tt.now); got != tt.want {
+				t.Errorf("expired(%s) = %v, want %v", tt.now, got, tt.want)
+			}
+		})
+	}
+	if (Suppression{RuleID: "host-network"}).expired(time.Now()) {
+		t.Error("suppression without expires has expired")
+	}
+}
+
+// setBaselineFlags, --baseline ve --write-baseline değerlerini test boyunca ayarlar.
+func setBaselineFlags(t *testing.T, baseline string, write bool) {
+	t.Helper()
+	previousBaseline, previousWrite := auditBaseline, auditWriteBaseline
+	auditBaseline, auditWriteBaseline = baseline, write
+	t.Cleanup(func() { auditBaseline, auditWriteBaseline = previousBaseline, previousWrite })
+}
+
+func TestLoadSuppressions(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{"valid", "suppressions:\n- ruleId: host-network\n  justification: node agent\n  expires: 2030-01-01\n", ""},
+		{"matches everything", "suppressions:\n- justification: too broad\n", "matches every finding"},
+		{"no justification", "suppressions:\n- ruleId: host-network\n", "has no justification"},
+		{"invalid expires", "suppressions:\n- ruleId: host-network\n  justification: x\n  expires: 01/02/2030\n", "invalid expires"},
+		{"not yaml", "suppressions: [\n", "failed to parse"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "ignore.yaml")
+			if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+			setBaselineFlags(t, path, false)
+			_, err := loadSuppressions(path)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if ExitCode(err) != ExitUsage || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("err = %v, want a usage error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	t.Run("missing file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "missing.yaml")
+		setBaselineFlags(t, "", false)
+		if _, err := loadSuppressions(path); err != nil {
+			t.Errorf("missing default file: %v", err)
+		}
+		setBaselineFlags(t, path, false)
+		if _, err := loadSuppressions(path); ExitCode(err) != ExitUsage {
+			t.Errorf("missing --baseline file: err %v, want a usage error", err)
+		}
+		setBaselineFlags(t, path, true)
+		if _, err := loadSuppressions(path); err != nil {
+			t.Errorf("missing --baseline file with --write-baseline: %v", err)
+		}
+	})
+}
+
+func TestWriteSuppressions(t *testing.T) {
+	added := []Suppression{{RuleID: "host-network", Resource: "pod/agent", FieldPath: "spec.hostNetwork", Justification: "Accepted in baseline"}}
+	tests := []struct {
+		name     string
+		existing string // boşsa dosya yoktur
+		want     string
+	}{
+		{
+			name: "new file",
+			want: `# kube-ai suppressions: every entry needs a justification; expires (YYYY-MM-DD) is optional.
+suppressions:
+- ruleId: host-network
+  resource: pod/agent
+  fieldPath: spec.hostNetwork
+  justification: Accepted in baseline
+`,
+		},
+		{
+			name: "comments are kept",
+			existing: `# Owned by the platform team.
+suppressions:
+  # Until the exporter is replaced
+  - ruleId: host-path-volume
+    resource: daemonset/exporter
+    justification: reads /proc of the node # ticket OPS-12
+    expires: 2030-01-01
+`,
+			want: `# Owned by the platform team.
+suppressions:
+  # Until the exporter is replaced
+  - ruleId: host-path-volume
+    resource: daemonset/exporter
+    justification: reads /proc of the node # ticket OPS-12
+    expires: 2030-01-01
+  - ruleId: host-network
+    resource: pod/agent
+    fieldPath: spec.hostNetwork
+    justification: Accepted in baseline
+`,
+		},
+		{
+			name:     "empty flow list",
+			existing: "suppressions: []\n",
+			want: `suppressions:
+- ruleId: host-network
+  resource: pod/agent
+  fieldPath: spec.hostNetwork
+  justification: Accepted in baseline
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), ".kube-ai-ignore.yaml")
+			if tt.existing != "" {
+				if err := os.WriteFile(path, []byte(tt.existing), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := writeSuppressions(path, added); err != nil {
+				t.Fatal(err)
+			}
+			got, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("file:\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("nothing added", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), ".kube-ai-ignore.yaml")
+		existing := "suppressions: [] # nothing yet\n"
+		if err := os.WriteFile(path, []byte(existing), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := writeSuppressions(path, nil); err != nil {
+			t.Fatal(err)
+		}
+		if got, _ := os.ReadFile(path); string(got) != existing {
+			t.Errorf("file was rewritten:\n%s", got)
+		}
+	})
+}
+
+func TestApplySuppressions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".kube-ai-ignore.yaml")
+	setBaselineFlags(t, path, true)
+	previous := suppressions
+	suppressions = &SuppressionFile{Suppressions: []Suppression{
+		{RuleID: "host-network", Justification: "node agents"},
+		{RuleID: "image-latest-tag", Justification: "dev only", Expires: "2020-01-01"},
+	}}
+	t.Cleanup(func() { suppressions = previous })
+
+	findings := []Finding{
+		{RuleID: "host-network", Resource: "pod/agent", FieldPath: "spec.hostNetwork"},
+		{RuleID: "image-latest-tag", Resource: "pod/agent", FieldPath: "spec.containers[0].image"},
+	}
+	kept, result, err := applySuppressions(findings)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Süresi dolan bastırmanın bulgusu baseline'a eklenir ve artık bastırılır
+	if len(kept) != 0 || result.Suppressed != 2 || result.Baselined != 1 {
+		t.Errorf("kept %d, suppressed %d, baselined %d; want 0, 2, 1", len(kept), result.Suppressed, result.Baselined)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "ruleId: image-latest-tag") || strings.Contains(string(data), "ruleId: host-network") {
+		t.Errorf("baseline file:\n%s", data)
+	}
+
+	setBaselineFlags(t, path, false)
+	kept, result, err = applySuppressions(findings[1:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(kept) != 0 || len(result.Expired) != 0 {
+		t.Errorf("second run: kept %v, expired %v", kept, result.Expired)
+	}
+}
@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	openai "github.com/sashabaranov/go-openai"
+	"sigs.k8s.io/yaml"
+)
+
+// ModelPrice, 1M token başına USD cinsinden fiyat.
+type ModelPrice struct {
+	Input  float64 `json:"input"`
+	Output float64 `json:"output"`
+}
+
+// Dahili fiyat tablosu (USD / 1M token). Güncel olmayabilir; kullanıcılar
+// ~/.config/kube-ai/pricing.yaml veya KUBE_AI_PRICING ile ezebilir:
+//
+//	gpt-4o:
+//	  input: 2.50
+//	  output: 10.00
+var defaultPrices = map[string]ModelPrice{
+	"gpt-4o":        {Input: 2.50, Output: 10.00},
+	"gpt-4o-mini":   {Input: 0.15, Output: 0.60},
+	"gpt-4.1":       {Input: 2.00, Output: 8.00},
+	"gpt-4.1-mini":  {Input: 0.40, Output: 1.60},
+	"gpt-4.1-nano":  {Input: 0.10, Output: 0.40},
+	"gpt-4-turbo":   {Input: 10.00, Output: 30.00},
+	"gpt-4":         {Input: 30.00, Output: 60.00},
+	"gpt-3.5-turbo": {Input: 0.50, Output: 1.50},
+	"o1":            {Input: 15.00, Output: 60.00},
+	"o1-mini":       {Input: 1.10, Output: 4.40},
+	"o3":            {Input: 2.00, Output: 8.00},
+	"o3-mini":       {Input: 1.10, Output: 4.40},
+	"o4-mini":       {Input: 1.10, Output: 4.40},
+}
+
+// pricingFile, kullanıcı fiyat tablosunun yolunu döndürür.
+func pricingFile() string {
+	if path := os.Getenv("KUBE_AI_PRICING"); path != "" {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".config", "kube-ai", "pricing.yaml")
+}
+
+// loadPrices, dahili tabloyu kullanıcı dosyasıyla birleştirir.
+func loadPrices() map[string]ModelPrice {
+	prices := make(map[string]ModelPrice, len(defaultPrices))
+	for model, price := range defaultPrices {
+		prices[model] = price
+	}
+
+	path := pricingFile()
+	if path == "" {
+		return prices
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return prices
+	}
+
+	var overrides map[string]ModelPrice
+	if err := yaml.Unmarshal(data, &overrides); err != nil {
+		fmt.Fprintf(statusOut, "⚠️ Warning: could not parse pricing file %s: %v\n", path, err)
+		return prices
+	}
+	for model, price := range overrides {
+		prices[strings.ToLower(model)] = price
+	}
+	return prices
+}
+
+// lookupPrice, model için fiyatı bulur. Tam eşleşme yoksa en uzun önek
+// kullanılır (ör. "gpt-4o-2024-08-06" -> "gpt-4o").
+func lookupPrice(prices map[string]ModelPrice, model string) (ModelPrice, bool) {
+	model = strings.ToLower(model)
+	if price, ok := prices[model]; ok {
+		return price, true
+	}
+
+	best := ""
+	for name := range prices {
+		if strings.HasPrefix(model, name) && len(name) > len(best) {
+			best = name
+		}
+	}
+	if best == "" {
+		return ModelPrice{}, false
+	}
+	return prices[best], true
+}
+
+// estimateCost, verilen kullanım için tahmini USD maliyetini hesaplar.
+func estimateCost(model string, usage openai.Usage) (float64, bool) {
+	price, ok := lookupPrice(loadPrices(), model)
+	if !ok {
+		return 0, false
+	}
+	return (float64(usage.PromptTokens)*price.Input + float64(usage.CompletionTokens)*price.Output) / 1_000_000, true
+}
+
+// addUsage, birden fazla isteğin token kullanımını toplar.
+func addUsage(a, b openai.Usage) openai.Usage {
+	a.PromptTokens += b.PromptTokens
+	a.CompletionTokens += b.CompletionTokens
+	a.TotalTokens += b.TotalTokens
+	return a
+}
+
+// printUsage, --count-tokens açıksa token kullanımını ve tahmini maliyeti yazdırır.
+func printUsage(model string, usage openai.Usage) {
+	if !CountTokens {
+		return
+	}
+	// Azure'da --model deployment adıdır; yanıttaki model adı fiyatlama için daha doğru.
+	if model == "" {
+		model = Model
+	}
+
+	fmt.Fprintf(statusOut, "\n📊 Token usage: prompt=%d completion=%d total=%d\n",
+		usage.PromptTokens, usage.CompletionTokens, usage.TotalTokens)
+
+	if cost, ok := estimateCost(model, usage); ok {
+		fmt.Fprintf(statusOut, "💰 Estimated cost: $%.6f (%s)\n", cost, model)
+	} else {
+		fmt.Fprintf(statusOut, "💰 Estimated cost: unknown (no price for model %q, add it to %s)\n", model, pricingFile())
+	}
+}
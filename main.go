@@ -1,22 +1,33 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/joho/godotenv"
 	"github.com/rmysatay/kube-ai/cmd"
 )
 
 func main() {
-	// .env dosyası varsa yükle
-	if err := godotenv.Load(); err != nil {
-		fmt.Println("⚠️ Warning: .env file not loaded.")
+	// .env dosyası varsa yükle; yoksa sessizce devam et
+	if err := godotenv.Load(); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		fmt.Println("⚠️ Warning: .env file not loaded:", err)
 	}
 
+	// Ctrl-C devam eden AI isteğini context üzerinden iptal eder
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
 	// CLI komutlarını çalıştır
-	if err := cmd.RootCmd.Execute(); err != nil {
-		fmt.Println("❌ Error executing command:", err)
-		os.Exit(1)
+	if err := cmd.Execute(ctx); err != nil {
+		stop()
+		// stdout -o json gibi makine çıktıları için temiz kalır
+		fmt.Fprintln(os.Stderr, "❌", err)
+		os.Exit(cmd.ExitCode(err))
 	}
 }